@@ -0,0 +1,36 @@
+// Package main prints the bcrypt hash of a password read from standard input, to be listed as password_hash in the users file
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func main() {
+
+	fmt.Fprint(os.Stderr, "password: ")
+
+	// the password is read from standard input so that it is not kept in the shell history
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		fmt.Fprintf(os.Stderr, "error reading password, %v\n", err)
+		os.Exit(1)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		fmt.Fprintln(os.Stderr, "password is empty")
+		os.Exit(1)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error generating password hash, %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(hash))
+}
@@ -3,15 +3,84 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
+// backends that can be selected with STORE_BACKEND
+const (
+	StoreBackendUpstream = "upstream"
+	// StoreBackendFile keeps objects in the embedded bbolt database at STORE_FILE_PATH
+	StoreBackendFile    = "file"
+	StoreBackendMemory  = "memory"
+	StoreBackendOverlay = "overlay"
+)
+
 // Config represents the data structure for the env fields that should be loaded to the application
 type Config struct {
-	BaseAPIURL    string `envconfig:"BASE_API_URL" required:"true"`
-	AuthSecretKey string `envconfig:"AUTH_SECRET_KEY" required:"true"`
+	BaseAPIURL    string `envconfig:"BASE_API_URL"`
+	AuthSecretKey string `envconfig:"AUTH_SECRET_KEY"`
 	Port          string `envconfig:"PORT" default:"8089"`
+
+	// AuthSigningAlg is the algorithm that signs auth tokens. HS256 signs with AuthSecretKey, while RS256, ES256 and EdDSA
+	// sign with the PEM encoded private key at AuthSigningKeyFile. AuthKeyID is sent as kid header and derived from the key when empty.
+	AuthSigningAlg     string `envconfig:"AUTH_SIGNING_ALG" default:"HS256"`
+	AuthSigningKeyFile string `envconfig:"AUTH_SIGNING_KEY_FILE"`
+	AuthKeyID          string `envconfig:"AUTH_KEY_ID"`
+
+	// AuthKeyringFile is a JSON file listing the current signing key along with previous keys that only verify tokens,
+	// it replaces the signing key configured above and is reloaded on SIGHUP
+	AuthKeyringFile string `envconfig:"AUTH_KEYRING_FILE"`
+
+	// UsersFile is a JSON file listing the users allowed to login with their role, tenant and bcrypt password hash,
+	// as shown in users.example.json. Password hashes are generated with cmd/hashpassword.
+	UsersFile string `envconfig:"USERS_FILE" default:"users.json"`
+	// LoginMaxFailures is the number of consecutive failed logins that locks out a username, lockout is disabled when it is 0
+	LoginMaxFailures     int           `envconfig:"LOGIN_MAX_FAILURES" default:"5"`
+	LoginLockoutDuration time.Duration `envconfig:"LOGIN_LOCKOUT_DURATION" default:"15m"`
+
+	// AccessTokenTTL is the lifetime of an auth token, RefreshTokenTTL is the lifetime of the refresh token used to renew it
+	AccessTokenTTL  time.Duration `envconfig:"ACCESS_TOKEN_TTL" default:"2m"`
+	RefreshTokenTTL time.Duration `envconfig:"REFRESH_TOKEN_TTL" default:"24h"`
+
+	// RevocationFile persists the auth tokens revoked on logout, they are only held in memory when it is empty
+	RevocationFile string `envconfig:"REVOCATION_FILE"`
+
+	// StoreBackend selects where objects are stored, BaseAPIURL is only required for the upstream and overlay backends.
+	// The file backend keeps objects in the embedded bbolt database at StoreFilePath, and the overlay backend reads objects
+	// from external API and keeps changes to them in that database.
+	StoreBackend  string `envconfig:"STORE_BACKEND" default:"upstream"`
+	StoreFilePath string `envconfig:"STORE_FILE_PATH" default:"data/objects.db"`
+	// StoreSeedFile is a JSON file of objects loaded by the memory backend, the reserved objects of external API are loaded when it is empty
+	StoreSeedFile string `envconfig:"STORE_SEED_FILE"`
+
+	// UpstreamAPIKey authenticates calls to external API, UpstreamCollection keeps objects in a private collection that requires it
+	UpstreamAPIKey     string `envconfig:"UPSTREAM_API_KEY"`
+	UpstreamCollection string `envconfig:"UPSTREAM_COLLECTION"`
+
+	// TenantCollections maps each tenant to the upstream collection, or local namespace, that holds its objects,
+	// such as team-a:collection-a,team-b:collection-b. Requests without a tenant are served from the default store.
+	TenantCollections map[string]string `envconfig:"TENANT_COLLECTIONS"`
+
+	// UpstreamTimeout limits a single call to external API, calls are only limited by the request context when it is 0
+	UpstreamTimeout             time.Duration `envconfig:"UPSTREAM_TIMEOUT" default:"10s"`
+	UpstreamMaxIdleConnsPerHost int           `envconfig:"UPSTREAM_MAX_IDLE_CONNS_PER_HOST" default:"10"`
+
+	// UpstreamMaxAttempts is the total number of attempts of a failed idempotent call to external API
+	UpstreamMaxAttempts    int           `envconfig:"UPSTREAM_MAX_ATTEMPTS" default:"3"`
+	UpstreamRetryBaseDelay time.Duration `envconfig:"UPSTREAM_RETRY_BASE_DELAY" default:"200ms"`
+	UpstreamRetryMaxDelay  time.Duration `envconfig:"UPSTREAM_RETRY_MAX_DELAY" default:"2s"`
+
+	// BreakerFailureThreshold is the number of consecutive failed calls to external API that opens the circuit breaker,
+	// the circuit breaker is disabled when it is 0
+	BreakerFailureThreshold int           `envconfig:"BREAKER_FAILURE_THRESHOLD" default:"5"`
+	BreakerOpenTimeout      time.Duration `envconfig:"BREAKER_OPEN_TIMEOUT" default:"30s"`
+	BreakerHalfOpenMaxCalls int           `envconfig:"BREAKER_HALF_OPEN_MAX_CALLS" default:"1"`
+
+	// CacheTTL is the duration for which objects read from store are cached, caching is disabled when it is 0
+	CacheTTL        time.Duration `envconfig:"CACHE_TTL" default:"30s"`
+	CacheMaxEntries int           `envconfig:"CACHE_MAX_ENTRIES" default:"256"`
 }
 
 // Load loads environment variables into Config and validates them.
@@ -21,5 +90,34 @@ func Load() *Config {
 		log.Fatalf("error loading environment variables: %v", err)
 	}
 
+	// the keyring file is validated once it is read
+	if cfg.AuthKeyringFile == "" {
+		switch cfg.AuthSigningAlg {
+		case "HS256":
+			if cfg.AuthSecretKey == "" {
+				log.Fatalf("error loading environment variables: AUTH_SECRET_KEY is required for signing algorithm HS256")
+			}
+		case "RS256", "ES256", "EdDSA":
+			if cfg.AuthSigningKeyFile == "" {
+				log.Fatalf("error loading environment variables: AUTH_SIGNING_KEY_FILE is required for signing algorithm %s", cfg.AuthSigningAlg)
+			}
+		default:
+			log.Fatalf("error loading environment variables: unknown signing algorithm %q", cfg.AuthSigningAlg)
+		}
+	}
+
+	switch cfg.StoreBackend {
+	case StoreBackendUpstream, StoreBackendOverlay:
+		if cfg.BaseAPIURL == "" {
+			log.Fatalf("error loading environment variables: BASE_API_URL is required for store backend %q", cfg.StoreBackend)
+		}
+		if (cfg.UpstreamCollection != "" || len(cfg.TenantCollections) > 0) && cfg.UpstreamAPIKey == "" {
+			log.Fatalf("error loading environment variables: UPSTREAM_API_KEY is required for UPSTREAM_COLLECTION and TENANT_COLLECTIONS")
+		}
+	case StoreBackendFile, StoreBackendMemory:
+	default:
+		log.Fatalf("error loading environment variables: unknown store backend %q", cfg.StoreBackend)
+	}
+
 	return &cfg
 }
@@ -12,3 +12,9 @@ require github.com/golang-jwt/jwt/v5 v5.3.0
 require github.com/kelseyhightower/envconfig v1.4.0
 
 require github.com/google/uuid v1.6.0
+
+require golang.org/x/crypto v0.31.0
+
+require go.etcd.io/bbolt v1.3.11
+
+require golang.org/x/sys v0.28.0 // indirect
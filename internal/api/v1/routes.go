@@ -6,17 +6,26 @@ import (
 
 	"github.com/harshitrajsinha/obj-rest/internal/handler"
 	"github.com/harshitrajsinha/obj-rest/internal/middleware"
+	"github.com/harshitrajsinha/obj-rest/internal/models"
 	"github.com/harshitrajsinha/obj-rest/internal/store"
 )
 
 // RegisterV1Routes registers all the routes for api version v1
-func RegisterV1Routes(mux *http.ServeMux, storeClient store.ObjectDataAccessor, authSecretKey string) {
+func RegisterV1Routes(mux *http.ServeMux, stores store.Resolver, loginHandler *handler.LoginHandler, keyring *models.Keyring, revoked models.RevocationList) {
 
-	mux.HandleFunc("GET /login", handler.Login)
+	mux.HandleFunc("GET /.well-known/jwks.json", loginHandler.JWKS)
+	mux.HandleFunc("POST /login", loginHandler.Login)
+	mux.HandleFunc("POST /token/refresh", loginHandler.RefreshToken)
+	mux.HandleFunc("POST /token/revoke", loginHandler.RevokeToken)
+	mux.HandleFunc("POST /logout", middleware.AuthMiddleware((loginHandler.Logout), keyring, revoked))
 
-	objHandler := handler.NewObjHandler(storeClient)
-	mux.HandleFunc("POST /api/v1/objects", middleware.AuthMiddleware((objHandler.CreateNewObj), authSecretKey))
-	mux.HandleFunc("GET /api/v1/objects", middleware.AuthMiddleware((objHandler.GetAllObj), authSecretKey))
-	mux.HandleFunc("GET /api/v1/objects/{id}", middleware.AuthMiddleware((objHandler.GetObjByID), authSecretKey))
+	objHandler := handler.NewTenantObjHandler(stores)
+	mux.HandleFunc("POST /api/v1/objects", middleware.AuthMiddleware((objHandler.CreateNewObj), keyring, revoked))
+	mux.HandleFunc("GET /api/v1/objects", middleware.AuthMiddleware((objHandler.GetAllObj), keyring, revoked))
+	mux.HandleFunc("GET /api/v1/objects/search", middleware.AuthMiddleware((objHandler.SearchObj), keyring, revoked))
+	mux.HandleFunc("GET /api/v1/objects/{id}", middleware.AuthMiddleware((objHandler.GetObjByID), keyring, revoked))
+	mux.HandleFunc("PUT /api/v1/objects/{id}", middleware.AuthMiddleware((objHandler.UpdateObj), keyring, revoked))
+	mux.HandleFunc("PATCH /api/v1/objects/{id}", middleware.AuthMiddleware((objHandler.PartiallyUpdateObj), keyring, revoked))
+	mux.HandleFunc("DELETE /api/v1/objects/{id}", middleware.AuthMiddleware((objHandler.DeleteObj), keyring, revoked))
 
 }
@@ -4,25 +4,38 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/harshitrajsinha/obj-rest/internal/middleware"
 	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/query"
 	"github.com/harshitrajsinha/obj-rest/internal/store"
 )
 
-// ObjHandler contains the store reference that will be used to fetch data
+// ObjHandler contains the reference to the stores that will be used to fetch data
 type ObjHandler struct {
-	store store.ObjectDataAccessor
+	stores store.Resolver
 }
 
-// NewObjHandler initializes and returns a new Handler instance with the provided store.UserStore dependency
-func NewObjHandler(store store.ObjectDataAccessor) *ObjHandler {
+// NewObjHandler initializes and returns a new Handler instance with the provided store.ObjectDataAccessor dependency
+func NewObjHandler(objStore store.ObjectDataAccessor) *ObjHandler {
 	return &ObjHandler{
-		store: store,
+		stores: store.NewTenantResolver(objStore, nil),
+	}
+}
+
+// NewTenantObjHandler initializes and returns a new Handler instance that serves each request from the store of its tenant
+func NewTenantObjHandler(stores store.Resolver) *ObjHandler {
+	return &ObjHandler{
+		stores: stores,
 	}
 }
 
@@ -31,19 +44,24 @@ func (h *ObjHandler) CreateNewObj(w http.ResponseWriter, r *http.Request) {
 
 	role := r.Context().Value(middleware.UserRole)
 	if role != "admin" {
-		if err := models.SendResponse(w, http.StatusForbidden, "Recognized but you are not allowed to perform this operation", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
 	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
 	defer cancel()
 
 	var payload models.ObjDataPayload
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		if err := models.SendResponse(w, http.StatusBadRequest, "Could not create object, invalid payload provided", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not create object, invalid payload provided"); err != nil {
 			log.Println(err)
 		}
 		return
@@ -51,7 +69,7 @@ func (h *ObjHandler) CreateNewObj(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if payload.Name == "" {
-		if err := models.SendResponse(w, http.StatusBadRequest, "Could not create object, invalid payload provided", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not create object, invalid payload provided"); err != nil {
 			log.Println(err)
 		}
 		return
@@ -67,12 +85,15 @@ func (h *ObjHandler) CreateNewObj(w http.ResponseWriter, r *http.Request) {
 	// 	},
 	// }
 
-	responseData, err := h.store.CreateNewObject(ctxWithTimeout, payload)
+	// a client provided idempotency key allows the store to safely retry creation of the object
+	if key := r.Header.Get(store.IdempotencyKeyHeader); key != "" {
+		ctxWithTimeout = store.WithIdempotencyKey(ctxWithTimeout, key)
+	}
+
+	responseData, err := objStore.CreateNewObject(ctxWithTimeout, payload)
 	if err != nil {
 		log.Println(err)
-		if err := models.SendResponse(w, http.StatusInternalServerError, "error creating object, try again later", nil); err != nil {
-			log.Println(err)
-		}
+		sendStoreError(w, r, err, "created")
 		return
 	}
 
@@ -90,26 +111,197 @@ func (h *ObjHandler) GetAllObj(w http.ResponseWriter, r *http.Request) {
 
 	role := r.Context().Value(middleware.UserRole)
 	if role != "admin" && role != "member" {
-		if err := models.SendResponse(w, http.StatusForbidden, "Recognized but you are not allowed to perform this operation", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
 	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
 	defer cancel()
 
-	objsList, err := h.store.GetAllObjects(ctxWithTimeout)
+	requestQuery := r.URL.Query()
+
+	projection, err := query.ParseFields(requestQuery.Get("fields"))
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	filters, err := query.ParseFilters(requestQuery)
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	sortKeys, err := query.ParseSort(requestQuery.Get("sort"))
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	page, err := query.ParsePageRequest(requestQuery.Get("limit"), requestQuery.Get("page_token"))
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	// repeated `id` query params request a batch lookup instead of the complete list, which cannot be filtered, sorted or paged
+	if ids := requestedIDs(r); len(ids) != 0 {
+		if len(filters) != 0 || len(sortKeys) != 0 || page.Limit != 0 || page.PageToken != "" {
+			if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, "id cannot be combined with filter, sort, limit or page_token"); err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		getObjsByIDs(ctxWithTimeout, w, r, objStore, ids, projection)
+		return
+	}
+
+	objsList, err = objStore.GetAllObjects(ctxWithTimeout)
+	if err != nil {
+		log.Println(err)
+		sendStoreError(w, r, err, "retrieved")
+		return
+	}
+
+	objsList = query.ApplyFilters(objsList, filters)
+
+	// Paginate orders objects by the sort keys
+	objsList, pagination, err := query.Paginate(objsList, page, filters, sortKeys)
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if err := models.SendPaginatedResponse(w, http.StatusOK, "Successfully retrieved all objects", projection.ApplyAll(objsList), pagination); err != nil {
+		log.Println(err)
+		return
+	}
+
+}
+
+// getObjsByIDs sends the objects matching the requested IDs along with the IDs that could not be found
+func getObjsByIDs(ctx context.Context, w http.ResponseWriter, r *http.Request, objStore store.ObjectDataAccessor, ids []string, projection *query.Projection) {
+
+	objsList, err := objStore.GetObjectsByIDs(ctx, ids...)
 	if err != nil {
 		log.Println(err)
+		if errors.Is(err, store.ErrNotFound) {
+			if err := models.SendError(w, r, http.StatusNotFound, models.ErrCodeNotFound, "None of the objects with given IDs are available"); err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		sendStoreError(w, r, err, "retrieved")
+		return
+	}
+
+	found := make(map[string]bool, len(objsList))
+	for _, obj := range objsList {
+		found[obj.ID] = true
+	}
+
+	result := models.BatchLookupResult{
+		Objects:    projection.ApplyAll(objsList),
+		MissingIDs: []string{},
+	}
+	for _, id := range ids {
+		if !found[id] {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully retrieved requested objects", result); err != nil {
+		log.Println(err)
+	}
+
+}
+
+// requestedIDs returns the unique, non-empty values of repeated `id` query params in the order they were provided
+func requestedIDs(r *http.Request) []string {
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range r.URL.Query()["id"] {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// SearchObj searches objects by their name and data, ranked by relevance
+func (h *ObjHandler) SearchObj(w http.ResponseWriter, r *http.Request) {
+
+	role := r.Context().Value(middleware.UserRole)
+	if role != "admin" && role != "member" {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
+	requestQuery := r.URL.Query()
+	text := requestQuery.Get("q")
+	if strings.TrimSpace(text) == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, "search text is missing, provide it as `q` query param"); err != nil {
+			log.Println(err)
+		}
 		return
 	}
 
-	if err := models.SendResponse(w, http.StatusOK, "Successfully retrieved all objects", objsList); err != nil {
+	var fields []string
+	for _, field := range requestQuery["field"] {
+		fields = append(fields, strings.Split(field, ",")...)
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	objsList, err := objStore.GetAllObjects(ctxWithTimeout)
+	if err != nil {
 		log.Println(err)
+		sendStoreError(w, r, err, "searched")
+		return
+	}
+
+	results, err := query.Search(objsList, text, fields)
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
+			log.Println(err)
+		}
 		return
 	}
 
+	if err := models.SendResponse(w, http.StatusOK, "Successfully searched objects", results); err != nil {
+		log.Println(err)
+	}
+
 }
 
 // GetObjByID getse an object from the object list based on ID
@@ -118,15 +310,28 @@ func (h *ObjHandler) GetObjByID(w http.ResponseWriter, r *http.Request) {
 	var objData models.ObjDataFromResponse
 	role := r.Context().Value(middleware.UserRole)
 	if role != "admin" && role != "member" {
-		if err := models.SendResponse(w, http.StatusForbidden, "Recognized but not allowed to perform action", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but not allowed to perform action"); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
 	id := r.PathValue("id")
 	if id == "" {
-		if err := models.SendResponse(w, http.StatusBadRequest, "object ID is missing", nil); err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeMissingID, "object ID is missing"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	projection, err := query.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidQuery, err.Error()); err != nil {
 			log.Println(err)
 		}
 		return
@@ -134,22 +339,291 @@ func (h *ObjHandler) GetObjByID(w http.ResponseWriter, r *http.Request) {
 
 	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
 	defer cancel()
-	objData, err := h.store.GetObjectByID(ctxWithTimeout, id)
+	objData, err = objStore.GetObjectByID(ctxWithTimeout, id)
 	if err != nil {
-		if strings.Contains(err.Error(), "error - no data retrieved in response") {
-			if err := models.SendResponse(w, http.StatusBadRequest, "Object with given ID not available", nil); err != nil {
-				log.Println(err)
-			}
-			return
+		log.Println(err)
+		sendStoreError(w, r, err, "retrieved")
+		return
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully retrieved object", projection.Apply(objData)); err != nil {
+		log.Println(err)
+	}
+
+}
+
+// UpdateObj replaces all the fields of an existing object based on ID
+func (h *ObjHandler) UpdateObj(w http.ResponseWriter, r *http.Request) {
+
+	role := r.Context().Value(middleware.UserRole)
+	if role != "admin" {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeMissingID, "object ID is missing"); err != nil {
+			log.Println(err)
 		}
-		if err := models.SendResponse(w, http.StatusInternalServerError, "could not retrieve requested object. Try again later", nil); err != nil {
+		return
+	}
+
+	var payload models.ObjDataPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not update object, invalid payload provided"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	if payload.Name == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not update object, invalid payload provided"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	responseData, err := objStore.UpdateObject(ctxWithTimeout, id, payload)
+	if err != nil {
+		log.Println(err)
+		sendStoreError(w, r, err, "updated")
+		return
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully updated the object", responseData); err != nil {
+		log.Println(err)
+	}
+
+}
+
+// PartiallyUpdateObj updates one or more fields of an existing object using JSON Merge Patch (RFC 7386) semantics
+func (h *ObjHandler) PartiallyUpdateObj(w http.ResponseWriter, r *http.Request) {
+
+	role := r.Context().Value(middleware.UserRole)
+	if role != "admin" {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeMissingID, "object ID is missing"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != models.MergePatchContentType && mediaType != "application/json") {
+		if err := models.SendError(w, r, http.StatusUnsupportedMediaType, models.ErrCodeUnsupportedMediaType, "Content-Type must be "+models.MergePatchContentType); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	var patch map[string]interface{}
+
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || len(patch) == 0 {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not update object, invalid payload provided"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validateMergePatch(patch); err != nil {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not update object, "+err.Error()); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	// merge patch is applied on the current state of the object, so that individual keys of data can be removed
+	currentObj, err := objStore.GetObjectByID(ctxWithTimeout, id)
+	if err != nil {
+		log.Println(err)
+		sendStoreError(w, r, err, "updated")
+		return
+	}
+
+	currentDoc := map[string]interface{}{
+		"name": currentObj.Name,
+	}
+	if currentObj.Data != nil {
+		currentDoc["data"] = currentObj.Data
+	}
+
+	mergedDoc := models.MergePatch(currentDoc, patch)
+	mergedData, _ := mergedDoc["data"].(map[string]interface{})
+
+	// an empty data map is omitted from the upstream payload and would leave the object unchanged
+	if len(mergedData) == 0 && len(currentObj.Data) != 0 {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not update object, data cannot be removed entirely"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	payload := models.ObjDataPayload{
+		Name: mergedDoc["name"].(string),
+		Data: mergedData,
+	}
+
+	responseData, err := objStore.UpdateObjectPartially(ctxWithTimeout, id, payload)
+	if err != nil {
+		log.Println(err)
+		sendStoreError(w, r, err, "updated")
+		return
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully updated the object", responseData); err != nil {
+		log.Println(err)
+	}
+
+}
+
+// DeleteObj deletes an existing object based on ID
+func (h *ObjHandler) DeleteObj(w http.ResponseWriter, r *http.Request) {
+
+	role := r.Context().Value(middleware.UserRole)
+	if role != "admin" {
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but you are not allowed to perform this operation"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	objStore, ok := h.storeFor(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeMissingID, "object ID is missing"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	result, err := objStore.DeleteObject(ctxWithTimeout, id)
+	if err != nil {
+		log.Println(err)
+		sendStoreError(w, r, err, "deleted")
+		return
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully deleted the object", result); err != nil {
+		log.Println(err)
+	}
+
+}
+
+// storeFor resolves the store of the tenant that the request has been authenticated for, sending an error response when there is none
+func (h *ObjHandler) storeFor(w http.ResponseWriter, r *http.Request) (store.ObjectDataAccessor, bool) {
+
+	tenant, _ := r.Context().Value(middleware.UserTenant).(string)
+
+	objStore, err := h.stores.Resolve(tenant)
+	if err != nil {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusForbidden, models.ErrCodeForbidden, "Recognized but your tenant is not allowed to access objects"); err != nil {
 			log.Println(err)
 		}
+		return nil, false
+	}
+
+	return objStore, true
+}
+
+// validateMergePatch checks that a merge patch document only touches the fields of an object that can be modified
+func validateMergePatch(patch map[string]interface{}) error {
+
+	for key, value := range patch {
+		switch key {
+		case "name":
+			if name, ok := value.(string); !ok || name == "" {
+				return fmt.Errorf("name must be a non-empty string")
+			}
+		case "data":
+			if value == nil {
+				return fmt.Errorf("data cannot be removed entirely")
+			}
+			if _, ok := value.(map[string]interface{}); !ok {
+				return fmt.Errorf("data must be an object")
+			}
+		default:
+			return fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	return nil
+}
+
+// sendStoreError sends the response for a failed call to store based on the type of error returned by it.
+// action describes what was attempted on the object, such as "updated", and is used in the response message.
+func sendStoreError(w http.ResponseWriter, r *http.Request, err error, action string) {
+
+	code, errCode, message := http.StatusInternalServerError, models.ErrCodeInternal, "Object could not be "+action+", try again later"
+
+	switch {
+	case sendCircuitOpen(w, r, err):
 		return
+	case errors.Is(err, store.ErrNotFound):
+		code, errCode, message = http.StatusNotFound, models.ErrCodeNotFound, "Object with given ID not available"
+	case errors.Is(err, store.ErrConflict):
+		// upstream refuses to modify reserved objects
+		code, errCode, message = http.StatusConflict, models.ErrCodeConflict, "Object with given ID is reserved and cannot be "+action
+	case errors.Is(err, context.DeadlineExceeded):
+		code, errCode, message = http.StatusGatewayTimeout, models.ErrCodeUpstreamTimeout, "Upstream service did not respond in time, try again later"
+	case errors.Is(err, store.ErrUpstreamUnavailable):
+		code, errCode, message = http.StatusBadGateway, models.ErrCodeUpstreamUnavailable, "Upstream service is unavailable, try again later"
+	}
+
+	if err := models.SendError(w, r, code, errCode, message); err != nil {
+		log.Println(err)
+	}
+}
+
+// sendCircuitOpen sends a 503 response telling the client when to retry if err is due to the circuit breaker
+// in front of store being open, and reports whether it did
+func sendCircuitOpen(w http.ResponseWriter, r *http.Request, err error) bool {
+
+	var openErr *store.CircuitOpenError
+	if !errors.As(err, &openErr) {
+		return false
 	}
 
-	if err := models.SendResponse(w, http.StatusOK, "Successfully retrieved object", objData); err != nil {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(openErr.RetryAfter.Seconds()))))
+	if err := models.SendError(w, r, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Service is temporarily unavailable, try again later"); err != nil {
 		log.Println(err)
 	}
 
+	return true
 }
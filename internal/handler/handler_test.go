@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -95,7 +96,7 @@ func (m MockStore) GetObjectByID(_ context.Context, ID string) (models.ObjDataFr
 	if ID == "1" {
 		return testObjectOne, nil
 	}
-	return models.ObjDataFromResponse{}, errors.New("error - no data retrieved in response")
+	return models.ObjDataFromResponse{}, store.ErrNotFound
 }
 
 // CreateNewObject returns a mock object based on requested ID
@@ -115,20 +116,47 @@ func (m MockStore) CreateNewObject(_ context.Context, payload models.ObjDataPayl
 	return newObject, nil
 }
 
-// // UpdateObject returns a mock object based on requested ID
-// func (m MockStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
-// 	return models.NewObj{}, nil
-// }
+// UpdateObject returns a mock updated object based on requested ID
+func (m MockStore) UpdateObject(_ context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	switch objID {
+	case "1":
+		return models.NewObj{
+			ID:   objID,
+			Name: payload.Name,
+			Data: payload.Data,
+		}, nil
+	case "7":
+		// reserved objects cannot be modified upstream
+		return models.NewObj{}, store.ErrConflict
+	}
+	return models.NewObj{}, store.ErrNotFound
+}
 
-// // UpdateObjectPartially returns a mock object based on requested ID
-// func (m MockStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
-// 	return models.NewObj{}, nil
-// }
+// UpdateObjectPartially returns a mock partially updated object based on requested ID
+func (m MockStore) UpdateObjectPartially(_ context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if objID == "1" {
+		return models.NewObj{
+			ID:   objID,
+			Name: payload.Name,
+			Data: payload.Data,
+		}, nil
+	}
+	return models.NewObj{}, store.ErrNotFound
+}
 
-// // DeleteObject returns a mock object based on requested ID
-// func (m MockStore) DeleteObject(ctx context.Context, objID string) (map[string]string, error) {
-// 	return nil, nil
-// }
+// DeleteObject returns a mock deletion result based on requested ID
+func (m MockStore) DeleteObject(_ context.Context, objID string) (models.DeleteResult, error) {
+	switch objID {
+	case "1":
+		return models.DeleteResult{
+			ID:      objID,
+			Message: "Object with id = 1 has been deleted.",
+		}, nil
+	case "7":
+		return models.DeleteResult{}, store.ErrConflict
+	}
+	return models.DeleteResult{}, store.ErrNotFound
+}
 
 // TestGetAllObj tests GetAllObj handler
 func TestGetAllObj(t *testing.T) {
@@ -642,8 +670,8 @@ func TestGetObjByID(t *testing.T) {
 		mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
 		mux.ServeHTTP(rec, req)
 
-		if rec.Result().StatusCode != http.StatusBadRequest {
-			t.Errorf("expected status code as 400, got %d", rec.Result().StatusCode)
+		if rec.Result().StatusCode != http.StatusNotFound {
+			t.Errorf("expected status code as 404, got %d", rec.Result().StatusCode)
 		}
 
 		if rec.Result().Header.Get("Content-Type") != "application/json" {
@@ -721,3 +749,745 @@ func TestGetObjByID(t *testing.T) {
 	})
 
 }
+
+// TestUpdateObj tests UpdateObj handler
+func TestUpdateObj(t *testing.T) {
+
+	objPayload := models.ObjDataPayload{
+		Name: "Apple MacBook Pro 16",
+		Data: map[string]interface{}{
+			"year":  2019,
+			"price": 2049.99,
+		},
+	}
+	payloadToSendInReq, _ := json.Marshal(objPayload)
+
+	tests := []struct {
+		name        string
+		role        string
+		id          string
+		payload     string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"member access", "member", "1", string(payloadToSendInReq), http.StatusForbidden, "Recognized but you are not allowed to perform this operation"},
+		{"invalid payload", "admin", "1", `{"data": {"price": 1}}`, http.StatusBadRequest, "Could not update object, invalid payload provided"},
+		{"unknown object", "admin", "99", string(payloadToSendInReq), http.StatusNotFound, "Object with given ID not available"},
+		{"reserved object", "admin", "7", string(payloadToSendInReq), http.StatusConflict, "Object with given ID is reserved and cannot be updated"},
+		{"admin access", "admin", "1", string(payloadToSendInReq), http.StatusOK, "Successfully updated the object"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/objects/"+tc.id, strings.NewReader(tc.payload))
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, tc.role)
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v1/objects/{id}", objHandler.UpdateObj)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			if rec.Result().Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected Header Content-Type as application/json, got %s", rec.Result().Header.Get("Content-Type"))
+			}
+
+			var testResponse map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			message, ok := testResponse["message"].(string)
+			if !ok {
+				t.Fatalf("message key missing or not a string: %v", testResponse)
+			}
+			if message != tc.wantMessage {
+				t.Errorf("unexpected message, got %s", message)
+			}
+		})
+	}
+}
+
+// TestPartiallyUpdateObj tests PartiallyUpdateObj handler
+func TestPartiallyUpdateObj(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		role        string
+		id          string
+		contentType string
+		patch       string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"member access", "member", "1", "application/merge-patch+json", `{"name": "Renamed"}`, http.StatusForbidden, "Recognized but you are not allowed to perform this operation"},
+		{"unsupported content type", "admin", "1", "text/plain", `{"name": "Renamed"}`, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json"},
+		{"unknown field", "admin", "1", "application/merge-patch+json", `{"id": "2"}`, http.StatusBadRequest, `Could not update object, unknown field "id"`},
+		{"null name", "admin", "1", "application/merge-patch+json", `{"name": null}`, http.StatusBadRequest, "Could not update object, name must be a non-empty string"},
+		{"unknown object", "admin", "99", "application/merge-patch+json", `{"name": "Renamed"}`, http.StatusNotFound, "Object with given ID not available"},
+		{"admin access", "admin", "1", "application/merge-patch+json", `{"data": {"Price": null, "color": "Silver"}}`, http.StatusOK, "Successfully updated the object"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/objects/"+tc.id, strings.NewReader(tc.patch))
+			req.Header.Set("Content-Type", tc.contentType)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, tc.role)
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /api/v1/objects/{id}", objHandler.PartiallyUpdateObj)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			var testResponse map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			message, ok := testResponse["message"].(string)
+			if !ok {
+				t.Fatalf("message key missing or not a string: %v", testResponse)
+			}
+			if message != tc.wantMessage {
+				t.Errorf("unexpected message, got %s", message)
+			}
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			data := testResponse["data"].(map[string]interface{})
+			if data["name"] != "Test Object One" {
+				t.Errorf("expected name to be unchanged, got %v", data["name"])
+			}
+			objData := data["data"].(map[string]interface{})
+			if _, ok := objData["Price"]; ok {
+				t.Errorf("expected Price to be removed, got %v", objData)
+			}
+			if objData["color"] != "Silver" {
+				t.Errorf("expected color as Silver, got %v", objData["color"])
+			}
+		})
+	}
+}
+
+// TestDeleteObj tests DeleteObj handler
+func TestDeleteObj(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		role        string
+		id          string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"member access", "member", "1", http.StatusForbidden, "Recognized but you are not allowed to perform this operation"},
+		{"unknown object", "admin", "99", http.StatusNotFound, "Object with given ID not available"},
+		{"reserved object", "admin", "7", http.StatusConflict, "Object with given ID is reserved and cannot be deleted"},
+		{"admin access", "admin", "1", http.StatusOK, "Successfully deleted the object"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/objects/"+tc.id, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, tc.role)
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /api/v1/objects/{id}", objHandler.DeleteObj)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			var testResponse map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			message, ok := testResponse["message"].(string)
+			if !ok {
+				t.Fatalf("message key missing or not a string: %v", testResponse)
+			}
+			if message != tc.wantMessage {
+				t.Errorf("unexpected message, got %s", message)
+			}
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			data := testResponse["data"].(map[string]interface{})
+			if data["id"] != tc.id {
+				t.Errorf("expected deleted ID as %s, got %v", tc.id, data["id"])
+			}
+		})
+	}
+}
+
+// TestGetAllObjByIDs tests batch lookup of GetAllObj handler
+func TestGetAllObjByIDs(t *testing.T) {
+	var mockStore MockStore
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objects?id=1&id=3&id=9&id=1", nil)
+	ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+	req = req.WithContext(ctxWithValue)
+
+	rec := httptest.NewRecorder()
+	objHandler := handler.NewObjHandler(mockStore)
+	objHandler.GetAllObj(rec, req)
+
+	if rec.Result().StatusCode != http.StatusOK {
+		t.Errorf("expected status code as 200, got %d", rec.Result().StatusCode)
+	}
+
+	var testResponse struct {
+		Message string `json:"message"`
+		Data    struct {
+			Objects    []models.ObjDataFromResponse `json:"objects"`
+			MissingIDs []string                     `json:"missing_ids"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if testResponse.Message != "Successfully retrieved requested objects" {
+		t.Errorf("unexpected message, got %s", testResponse.Message)
+	}
+
+	if len(testResponse.Data.Objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(testResponse.Data.Objects))
+	}
+	if testResponse.Data.Objects[0].ID != "1" || testResponse.Data.Objects[1].ID != "3" {
+		t.Errorf("expected objects with ID 1 and 3, got %v", testResponse.Data.Objects)
+	}
+
+	if len(testResponse.Data.MissingIDs) != 1 || testResponse.Data.MissingIDs[0] != "9" {
+		t.Errorf("expected missing IDs as [9], got %v", testResponse.Data.MissingIDs)
+	}
+}
+
+// TestGetAllObjByIDsWithListParams tests that batch lookup of GetAllObj handler rejects the query params of the complete list
+func TestGetAllObjByIDsWithListParams(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"with fields", "id=1&fields=name", http.StatusOK},
+		{"with filter", "id=1&filter[name]=x", http.StatusBadRequest},
+		{"with malformed filter", "id=1&filter[name", http.StatusBadRequest},
+		{"with sort", "id=1&sort=name", http.StatusBadRequest},
+		{"with limit", "id=1&limit=1", http.StatusBadRequest},
+		{"with page token", "id=1&page_token=abc", http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/objects?"+tc.query, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(MockStore{})
+			objHandler.GetAllObj(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+		})
+	}
+}
+
+// TestSearchObj tests SearchObj handler
+func TestSearchObj(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		target      string
+		wantStatus  int
+		wantMessage string
+		wantCount   int
+	}{
+		{"missing search text", "/api/v1/objects/search", http.StatusBadRequest, "search text is missing, provide it as `q` query param", 0},
+		{"unknown field", "/api/v1/objects/search?q=test&field=price", http.StatusBadRequest, `unknown search field "price"`, 0},
+		{"matching object", "/api/v1/objects/search?q=OBJECT&field=name", http.StatusOK, "Successfully searched objects", 1},
+		{"no matching object", "/api/v1/objects/search?q=laptop", http.StatusOK, "Successfully searched objects", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+			objHandler.SearchObj(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			var testResponse struct {
+				Message string                       `json:"message"`
+				Data    []models.ObjDataFromResponse `json:"data"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			if testResponse.Message != tc.wantMessage {
+				t.Errorf("unexpected message, got %s", testResponse.Message)
+			}
+			if len(testResponse.Data) != tc.wantCount {
+				t.Errorf("expected %d objects, got %d", tc.wantCount, len(testResponse.Data))
+			}
+		})
+	}
+}
+
+// TestGetAllObjWithFilters tests filtering of GetAllObj handler
+func TestGetAllObjWithFilters(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantCount  int
+	}{
+		{"matching filter", "/api/v1/objects?filter[name][contains]=test", http.StatusOK, 1},
+		{"non-matching filter", "/api/v1/objects?filter[data.Price][gt]=1000", http.StatusOK, 0},
+		{"unknown operator", "/api/v1/objects?filter[data.Price][near]=1000", http.StatusBadRequest, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+			objHandler.GetAllObj(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			var testResponse struct {
+				Data []models.ObjDataFromResponse `json:"data"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if len(testResponse.Data) != tc.wantCount {
+				t.Errorf("expected %d objects, got %d", tc.wantCount, len(testResponse.Data))
+			}
+		})
+	}
+}
+
+// TestGetAllObjWithPagination tests sorting and pagination of GetAllObj handler
+func TestGetAllObjWithPagination(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantTotal  int
+	}{
+		{"complete list", "/api/v1/objects?sort=-name", http.StatusOK, 1},
+		{"limited list", "/api/v1/objects?sort=name,-data.price&limit=1", http.StatusOK, 1},
+		{"invalid limit", "/api/v1/objects?limit=1000", http.StatusBadRequest, 0},
+		{"invalid sort", "/api/v1/objects?sort=price", http.StatusBadRequest, 0},
+		{"invalid page token", "/api/v1/objects?page_token=abc", http.StatusBadRequest, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStore MockStore
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+			objHandler.GetAllObj(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			var testResponse models.Response
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			if testResponse.Pagination == nil {
+				t.Fatalf("expected pagination metadata, got none")
+			}
+			if testResponse.Pagination.TotalCount != tc.wantTotal {
+				t.Errorf("expected total count as %d, got %d", tc.wantTotal, testResponse.Pagination.TotalCount)
+			}
+			if testResponse.Pagination.NextPageToken != "" {
+				t.Errorf("expected no next page token, got %s", testResponse.Pagination.NextPageToken)
+			}
+		})
+	}
+}
+
+// TestGetObjByIDWithFields tests sparse fieldsets of GetObjByID handler
+func TestGetObjByIDWithFields(t *testing.T) {
+	var mockStore MockStore
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objects/1?fields=name,data.price", nil)
+	ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+	req = req.WithContext(ctxWithValue)
+
+	rec := httptest.NewRecorder()
+	objHandler := handler.NewObjHandler(mockStore)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Result().StatusCode != http.StatusOK {
+		t.Errorf("expected status code as 200, got %d", rec.Result().StatusCode)
+	}
+
+	var testResponse map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	data := testResponse["data"].(map[string]interface{})
+	if _, ok := data["id"]; ok {
+		t.Errorf("expected id to be left out, got %v", data)
+	}
+	if data["name"] != "Test Object One" {
+		t.Errorf("expected name as Test Object One, got %v", data["name"])
+	}
+	objData := data["data"].(map[string]interface{})
+	if len(objData) != 1 || objData["Price"] != "1" {
+		t.Errorf("expected only Price in data, got %v", objData)
+	}
+}
+
+// UnavailableMockStore embeds ObjectDataAccessor and rejects calls as an open circuit breaker would
+type UnavailableMockStore struct {
+	store.ObjectDataAccessor
+}
+
+// GetObjectByID returns the error of an open circuit breaker
+func (m UnavailableMockStore) GetObjectByID(_ context.Context, _ string) (models.ObjDataFromResponse, error) {
+	return models.ObjDataFromResponse{}, &store.CircuitOpenError{RetryAfter: 1500 * time.Millisecond}
+}
+
+// TestGetObjByIDCircuitOpen tests GetObjByID handler while the circuit breaker is open
+func TestGetObjByIDCircuitOpen(t *testing.T) {
+	var mockStore UnavailableMockStore
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objects/1", nil)
+	ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+	req = req.WithContext(ctxWithValue)
+
+	rec := httptest.NewRecorder()
+	objHandler := handler.NewObjHandler(mockStore)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Result().StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status code as 503, got %d", rec.Result().StatusCode)
+	}
+
+	if rec.Result().Header.Get("Retry-After") != "2" {
+		t.Errorf("expected Retry-After header as 2, got %s", rec.Result().Header.Get("Retry-After"))
+	}
+}
+
+// ErrorMockStore embeds ObjectDataAccessor and fails every call with err
+type ErrorMockStore struct {
+	store.ObjectDataAccessor
+	err error
+}
+
+// GetAllObjects returns the configured error, or an empty list when there is none
+func (m ErrorMockStore) GetAllObjects(_ context.Context) ([]models.ObjDataFromResponse, error) {
+	if m.err == nil {
+		return []models.ObjDataFromResponse{}, nil
+	}
+	return nil, m.err
+}
+
+// UpdateObject returns the configured error
+func (m ErrorMockStore) UpdateObject(_ context.Context, _ string, _ models.ObjDataPayload) (models.NewObj, error) {
+	return models.NewObj{}, m.err
+}
+
+// TestUpdateObjStoreErrors tests the responses of UpdateObj handler for the errors returned by store
+func TestUpdateObjStoreErrors(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"not found", &store.UpstreamStatusError{StatusCode: http.StatusNotFound}, http.StatusNotFound},
+		{"reserved object", &store.UpstreamStatusError{StatusCode: http.StatusMethodNotAllowed}, http.StatusConflict},
+		{"upstream failure", &store.UpstreamStatusError{StatusCode: http.StatusInternalServerError}, http.StatusBadGateway},
+		{"upstream timeout", fmt.Errorf("error sending request, %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"unknown error", errors.New("unexpected"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStore := ErrorMockStore{err: tc.err}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/objects/1", strings.NewReader(`{"name": "Updated"}`))
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "admin")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v1/objects/{id}", objHandler.UpdateObj)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+		})
+	}
+}
+
+// TestGetObjByIDProblemResponse tests RFC 7807 error responses of GetObjByID handler
+func TestGetObjByIDProblemResponse(t *testing.T) {
+	var mockStore MockStore
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objects/2", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json;q=0.9")
+	ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+	req = req.WithContext(ctxWithValue)
+
+	rec := httptest.NewRecorder()
+	objHandler := handler.NewObjHandler(mockStore)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Result().StatusCode != http.StatusNotFound {
+		t.Errorf("expected status code as 404, got %d", rec.Result().StatusCode)
+	}
+
+	if rec.Result().Header.Get("Content-Type") != "application/problem+json" {
+		t.Errorf("expected Header Content-Type as application/problem+json, got %s", rec.Result().Header.Get("Content-Type"))
+	}
+
+	var problem models.Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	wantProblem := models.Problem{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "Object with given ID not available",
+		Instance: "/api/v1/objects/2",
+		Code:     models.ErrCodeNotFound,
+	}
+	if problem != wantProblem {
+		t.Errorf("expected problem %v, got %v", wantProblem, problem)
+	}
+}
+
+// TestGetAllObjStoreErrors tests the responses of GetAllObj handler for the errors returned by store
+func TestGetAllObjStoreErrors(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"upstream failure", &store.UpstreamStatusError{StatusCode: http.StatusServiceUnavailable}, http.StatusBadGateway, "Upstream service is unavailable, try again later"},
+		{"upstream timeout", fmt.Errorf("error sending request, %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "Upstream service did not respond in time, try again later"},
+		{"circuit open", &store.CircuitOpenError{RetryAfter: time.Second}, http.StatusServiceUnavailable, "Service is temporarily unavailable, try again later"},
+		{"unknown error", errors.New("error parsing response of all objects"), http.StatusInternalServerError, "Object could not be retrieved, try again later"},
+		{"empty list", nil, http.StatusOK, "Successfully retrieved all objects"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStore := ErrorMockStore{err: tc.err}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/objects", nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+			objHandler := handler.NewObjHandler(mockStore)
+			objHandler.GetAllObj(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+
+			if rec.Result().Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected Header Content-Type as application/json, got %s", rec.Result().Header.Get("Content-Type"))
+			}
+
+			var testResponse map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+				t.Fatalf("expected an error response body, got %v", err)
+			}
+
+			message, ok := testResponse["message"].(string)
+			if !ok {
+				t.Fatalf("message key missing or not a string: %v", testResponse)
+			}
+			if message != tc.wantMessage {
+				t.Errorf("unexpected message, got %s", message)
+			}
+
+			if tc.err == nil {
+				if data, ok := testResponse["data"].([]interface{}); !ok || len(data) != 0 {
+					t.Errorf("expected data as empty list, got %v", testResponse["data"])
+				}
+			}
+		})
+	}
+}
+
+// TestObjLifecycleWithMemoryStore tests the handlers against MemoryStore seeded with reserved objects
+func TestObjLifecycleWithMemoryStore(t *testing.T) {
+
+	objHandler := handler.NewObjHandler(store.NewMemoryStore(store.ReservedObjects()))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/objects", objHandler.CreateNewObj)
+	mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
+	mux.HandleFunc("PATCH /api/v1/objects/{id}", objHandler.PartiallyUpdateObj)
+	mux.HandleFunc("DELETE /api/v1/objects/{id}", objHandler.DeleteObj)
+
+	serve := func(method string, target string, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserRole, "admin"))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		var testResponse map[string]interface{}
+		if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		data, _ := testResponse["data"].(map[string]interface{})
+		return rec.Result().StatusCode, data
+	}
+
+	if status, data := serve(http.MethodGet, "/api/v1/objects/7", ""); status != http.StatusOK || data["name"] != "Apple MacBook Pro 16" {
+		t.Fatalf("expected reserved object 7, got %d %v", status, data)
+	}
+
+	status, data := serve(http.MethodPost, "/api/v1/objects", `{"name": "Apple AirPods Pro", "data": {"color": "White"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status code as 200, got %d", status)
+	}
+	id, _ := data["id"].(string)
+
+	if status, data := serve(http.MethodPatch, "/api/v1/objects/"+id, `{"data": {"color": null, "price": 249}}`); status != http.StatusOK {
+		t.Fatalf("expected status code as 200, got %d", status)
+	} else if objData := data["data"].(map[string]interface{}); objData["color"] != nil || objData["price"] != 249.0 {
+		t.Errorf("unexpected data after patch %v", objData)
+	}
+
+	if status, _ := serve(http.MethodDelete, "/api/v1/objects/"+id, ""); status != http.StatusOK {
+		t.Fatalf("expected status code as 200, got %d", status)
+	}
+
+	if status, _ := serve(http.MethodGet, "/api/v1/objects/"+id, ""); status != http.StatusNotFound {
+		t.Errorf("expected status code as 404, got %d", status)
+	}
+}
+
+// TestTenantObjHandler tests that requests are served from the store of their tenant
+func TestTenantObjHandler(t *testing.T) {
+
+	defaultStore := store.NewMemoryStore(store.ReservedObjects())
+	teamStore := store.NewMemoryStore([]models.NewObj{{ID: "team-1", Name: "Team Object"}})
+
+	objHandler := handler.NewTenantObjHandler(store.NewTenantResolver(defaultStore, map[string]store.ObjectDataAccessor{
+		"team-a": teamStore,
+	}))
+
+	tests := []struct {
+		name       string
+		tenant     string
+		id         string
+		wantStatus int
+	}{
+		{"default store without tenant", "", "7", http.StatusOK},
+		{"tenant store", "team-a", "team-1", http.StatusOK},
+		{"default objects hidden from tenant", "team-a", "7", http.StatusNotFound},
+		{"unknown tenant", "team-b", "7", http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/objects/"+tc.id, nil)
+			ctxWithValue := context.WithValue(req.Context(), middleware.UserRole, "member")
+			ctxWithValue = context.WithValue(ctxWithValue, middleware.UserTenant, tc.tenant)
+			req = req.WithContext(ctxWithValue)
+
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/objects/{id}", objHandler.GetObjByID)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+		})
+	}
+}
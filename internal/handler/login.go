@@ -2,43 +2,308 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
 )
 
-// Login verifies user role and create auth token for the user
-func Login(w http.ResponseWriter, r *http.Request) {
+// LoginSettings defines the lifetime of the tokens issued on login and when a username is locked out
+type LoginSettings struct {
+	// MaxFailures is the number of consecutive failed logins that locks out a username for LockoutDuration, lockout is disabled when it is 0
+	MaxFailures     int
+	LockoutDuration time.Duration
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// LoginHandler contains the users that are allowed to login along with the keys used to sign their auth tokens
+type LoginHandler struct {
+	users         store.UserStore
+	refreshTokens store.RefreshTokenStore
+	revoked       models.RevocationList
+	keyring       *models.Keyring
+	settings      LoginSettings
+	lockout       *loginLockout
+
+	// dummyHash is compared against when the user does not exist, so that unknown usernames take as long as wrong passwords
+	dummyHash []byte
+}
+
+// NewLoginHandler initializes and returns a new LoginHandler instance
+func NewLoginHandler(users store.UserStore, refreshTokens store.RefreshTokenStore, revoked models.RevocationList, keyring *models.Keyring, settings LoginSettings) *LoginHandler {
+
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("obj-rest"), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatalf("error generating password hash, %v", err)
+	}
+
+	return &LoginHandler{
+		users:         users,
+		refreshTokens: refreshTokens,
+		revoked:       revoked,
+		keyring:       keyring,
+		settings:      settings,
+		lockout:       newLoginLockout(settings.MaxFailures, settings.LockoutDuration),
+		dummyHash:     dummyHash,
+	}
+}
+
+// Login verifies the credentials of a user and creates an auth token with the role and tenant of the user, along with a refresh token
+func (h *LoginHandler) Login(w http.ResponseWriter, r *http.Request) {
+
+	var payload models.LoginPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Username == "" || payload.Password == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "username and password are required"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	user, err := h.users.GetUser(ctxWithTimeout, payload.Username)
+	if err != nil && !errors.Is(err, store.ErrUserNotFound) {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not authenticate. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	userExists := err == nil
 
-	requestQuery := r.URL.Query()
-	role := requestQuery.Get("role")
-	if role != "admin" && role != "member" {
-		if err := models.SendResponse(w, http.StatusBadRequest, "invalid role option", nil); err != nil {
+	// every username is locked out alike, so that lockouts do not tell which usernames exist
+	if retryAfter := h.lockout.begin(payload.Username); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		if err := models.SendError(w, r, http.StatusTooManyRequests, models.ErrCodeAccountLocked, "Too many failed login attempts, try again later"); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
-	secretKey := os.Getenv("AUTH_SECRET_KEY")
-	if secretKey == "" {
-		log.Fatalf("Auth key not found")
+	passwordHash := h.dummyHash
+	if userExists {
+		passwordHash = []byte(user.PasswordHash)
 	}
 
-	token, err := models.GenerateAuthToken(role, secretKey)
+	loginFailed := bcrypt.CompareHashAndPassword(passwordHash, []byte(payload.Password)) != nil || !userExists
+	h.lockout.finish(payload.Username, loginFailed)
+
+	if loginFailed {
+		log.Printf("failed login attempt for user %q", payload.Username)
+		if err := models.SendError(w, r, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Invalid username or password"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	refreshToken, refreshHash, err := models.GenerateRefreshToken()
+	if err == nil {
+		err = h.refreshTokens.SaveRefreshToken(ctxWithTimeout, models.RefreshToken{
+			Hash:      refreshHash,
+			Family:    uuid.NewString(),
+			Username:  user.Username,
+			ExpiresAt: time.Now().Add(h.settings.RefreshTokenTTL),
+		})
+	}
 	if err != nil {
-		if err := models.SendResponse(w, http.StatusInternalServerError, "could not authenticate. Try again later", nil); err != nil {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not authenticate. Try again later"); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
-	tokenData := map[string]string{
-		"token": token,
+	h.sendTokens(w, r, user, refreshToken, "Successfully authenticated")
+}
+
+// sendTokens creates an auth token for user and sends it along with its refresh token
+func (h *LoginHandler) sendTokens(w http.ResponseWriter, r *http.Request, user models.User, refreshToken string, message string) {
+
+	token, err := models.GenerateAuthToken(user.Role, user.Tenant, h.settings.AccessTokenTTL, h.keyring)
+	if err != nil {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not authenticate. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
 	}
 
-	if err := models.SendResponse(w, http.StatusCreated, "Successfully authenticated", tokenData); err != nil {
+	tokenData := models.TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(h.settings.AccessTokenTTL.Seconds()),
+	}
+
+	if err := models.SendResponse(w, http.StatusCreated, message, tokenData); err != nil {
 		log.Println(err)
 	}
 }
+
+// pendingLoginRetryAfter is sent as Retry-After when the logins of a username being verified could already lock it out
+const pendingLoginRetryAfter = time.Second
+
+// maxLockoutRecords bounds the number of usernames whose failed logins are recorded
+const maxLockoutRecords = 10000
+
+// loginFailures records the consecutive failed logins of a username along with its logins being verified
+type loginFailures struct {
+	count       int
+	inFlight    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// loginLockout locks out usernames after repeated failed logins, whether they exist or not.
+// Records are pruned once their failures are no longer consecutive, and at most maxLockoutRecords are kept.
+type loginLockout struct {
+	maxFailures int
+	duration    time.Duration
+
+	mu        sync.Mutex
+	failures  map[string]*loginFailures
+	lastPrune time.Time
+}
+
+func newLoginLockout(maxFailures int, duration time.Duration) *loginLockout {
+	return &loginLockout{
+		maxFailures: maxFailures,
+		duration:    duration,
+		failures:    make(map[string]*loginFailures),
+		lastPrune:   time.Now(),
+	}
+}
+
+// begin reserves a login attempt of username, which must be completed with finish. Attempts being verified count as failures
+// until they finish, so that parallel attempts cannot exceed the maximum number of failures. It returns the time remaining
+// until username can login again instead, which is 0 when the attempt has been reserved.
+func (l *loginLockout) begin(username string) time.Duration {
+
+	if l.maxFailures < 1 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > l.duration {
+		l.prune(now)
+	}
+
+	f, ok := l.failures[username]
+	if !ok {
+		if len(l.failures) >= maxLockoutRecords {
+			l.prune(now)
+			if len(l.failures) >= maxLockoutRecords {
+				l.evict()
+			}
+		}
+		f = &loginFailures{}
+		l.failures[username] = f
+	}
+
+	if remaining := f.lockedUntil.Sub(now); remaining > 0 {
+		return remaining
+	}
+
+	// failures are only consecutive while they are less than the lockout duration apart
+	if now.Sub(f.lastFailure) > l.duration {
+		f.count = 0
+	}
+
+	if f.count+f.inFlight >= l.maxFailures {
+		return pendingLoginRetryAfter
+	}
+	f.inFlight++
+
+	return 0
+}
+
+// finish records the outcome of an attempt reserved with begin, locking out username once it reaches the maximum number of failures
+func (l *loginLockout) finish(username string, failed bool) {
+
+	if l.maxFailures < 1 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, ok := l.failures[username]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	f.inFlight--
+	if failed {
+		f.count++
+		f.lastFailure = now
+		if f.count >= l.maxFailures {
+			f.lockedUntil = now.Add(l.duration)
+			f.count = 0
+		}
+	} else {
+		f.count = 0
+	}
+
+	if f.inFlight == 0 && f.count == 0 && !f.lockedUntil.After(now) {
+		delete(l.failures, username)
+	}
+}
+
+// prune deletes the records that no longer affect a login, it must be called with the lock held
+func (l *loginLockout) prune(now time.Time) {
+
+	for username, f := range l.failures {
+		if f.inFlight == 0 && !f.lockedUntil.After(now) && now.Sub(f.lastFailure) > l.duration {
+			delete(l.failures, username)
+		}
+	}
+	l.lastPrune = now
+}
+
+// evict deletes the record with the oldest failure, preferring usernames that are not locked out, to make room for another username.
+// It must be called with the lock held.
+func (l *loginLockout) evict() {
+
+	now := time.Now()
+	var oldest string
+	var oldestFailure *loginFailures
+	for username, f := range l.failures {
+		if f.inFlight > 0 {
+			continue
+		}
+		if oldestFailure == nil || evictsBefore(f, oldestFailure, now) {
+			oldest, oldestFailure = username, f
+		}
+	}
+
+	if oldestFailure != nil {
+		delete(l.failures, oldest)
+	}
+}
+
+// evictsBefore reports whether record a is evicted before record b
+func evictsBefore(a *loginFailures, b *loginFailures, now time.Time) bool {
+	aLocked, bLocked := a.lockedUntil.After(now), b.lockedUntil.After(now)
+	if aLocked != bLocked {
+		return bLocked
+	}
+	return a.lastFailure.Before(b.lastFailure)
+}
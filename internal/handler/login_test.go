@@ -0,0 +1,469 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/harshitrajsinha/obj-rest/internal/handler"
+	"github.com/harshitrajsinha/obj-rest/internal/middleware"
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// MockUserStore implements UserStore with a fixed list of users
+type MockUserStore map[string]models.User
+
+// GetUser returns a mock user
+func (m MockUserStore) GetUser(_ context.Context, username string) (models.User, error) {
+	user, ok := m[username]
+	if !ok {
+		return models.User{}, store.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// newMockUserStore returns users alice, an admin of tenant team-a, and bob, a member, whose password is their username
+func newMockUserStore(t *testing.T) MockUserStore {
+	users := MockUserStore{}
+	for username, role := range map[string]string{"alice": "admin", "bob": "member"} {
+		hash, err := bcrypt.GenerateFromPassword([]byte(username), bcrypt.MinCost)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		users[username] = models.User{Username: username, PasswordHash: string(hash), Role: role}
+	}
+	alice := users["alice"]
+	alice.Tenant = "team-a"
+	users["alice"] = alice
+	return users
+}
+
+// testKeyring signs the auth tokens issued by LoginHandler under test
+var testKeyring = newTestKeyring()
+
+func newTestKeyring() *models.Keyring {
+	key, _ := models.NewHMACKey("test", "secret")
+	keyring, _ := models.NewKeyring(key)
+	return keyring
+}
+
+// loginSettings are the settings of LoginHandler under test
+var loginSettings = handler.LoginSettings{
+	MaxFailures:     2,
+	LockoutDuration: time.Minute,
+	AccessTokenTTL:  time.Minute,
+	RefreshTokenTTL: time.Hour,
+}
+
+// decodeTokens decodes the tokens sent on login and refresh
+func decodeTokens(t *testing.T, rec *httptest.ResponseRecorder) models.TokenPair {
+	var testResponse struct {
+		Data models.TokenPair `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&testResponse); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	return testResponse.Data
+}
+
+// TestLogin tests Login handler
+func TestLogin(t *testing.T) {
+
+	loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, testKeyring, loginSettings)
+
+	login := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		loginHandler.Login(rec, req)
+		return rec
+	}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		rec := login(`{"username": "alice", "password": "alice"}`)
+		if rec.Result().StatusCode != http.StatusCreated {
+			t.Fatalf("expected status code as 201, got %d", rec.Result().StatusCode)
+		}
+
+		tokens := decodeTokens(t, rec)
+		if tokens.RefreshToken == "" || tokens.ExpiresIn != 60 {
+			t.Errorf("expected refresh token and expiry of auth token, got %+v", tokens)
+		}
+
+		claims, err := models.VerifyAuthToken(tokens.Token, testKeyring, nil)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if claims.Role != "admin" || claims.Tenant != "team-a" {
+			t.Errorf("expected role and tenant of user, got %s %s", claims.Role, claims.Tenant)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"missing password", `{"username": "bob"}`, http.StatusBadRequest},
+		{"invalid payload", `username=bob`, http.StatusBadRequest},
+		{"unknown user", `{"username": "mallory", "password": "mallory"}`, http.StatusUnauthorized},
+		{"role is not taken from request", `{"username": "bob", "password": "bob", "role": "admin"}`, http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if rec := login(tc.body); rec.Result().StatusCode != tc.wantStatus {
+				t.Errorf("expected status code as %d, got %d", tc.wantStatus, rec.Result().StatusCode)
+			}
+		})
+	}
+
+	t.Run("lockout after repeated failures", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if rec := login(`{"username": "bob", "password": "wrong"}`); rec.Result().StatusCode != http.StatusUnauthorized {
+				t.Fatalf("expected status code as 401, got %d", rec.Result().StatusCode)
+			}
+		}
+
+		rec := login(`{"username": "bob", "password": "bob"}`)
+		if rec.Result().StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("expected status code as 429, got %d", rec.Result().StatusCode)
+		}
+		if rec.Result().Header.Get("Retry-After") != "60" {
+			t.Errorf("expected Retry-After as 60, got %s", rec.Result().Header.Get("Retry-After"))
+		}
+
+		if rec := login(`{"username": "alice", "password": "alice"}`); rec.Result().StatusCode != http.StatusCreated {
+			t.Errorf("expected other users not to be locked out, got %d", rec.Result().StatusCode)
+		}
+	})
+
+	t.Run("unknown users are locked out alike", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if rec := login(`{"username": "eve", "password": "wrong"}`); rec.Result().StatusCode != http.StatusUnauthorized {
+				t.Fatalf("expected status code as 401, got %d", rec.Result().StatusCode)
+			}
+		}
+
+		rec := login(`{"username": "eve", "password": "eve"}`)
+		if rec.Result().StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("expected status code as 429, got %d", rec.Result().StatusCode)
+		}
+		if rec.Result().Header.Get("Retry-After") != "60" {
+			t.Errorf("expected Retry-After as 60, got %s", rec.Result().Header.Get("Retry-After"))
+		}
+	})
+
+	t.Run("parallel failures do not exceed maximum", func(t *testing.T) {
+		loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, testKeyring, loginSettings)
+
+		statusCodes := make(chan int, 10)
+		var wg sync.WaitGroup
+		for i := 0; i < cap(statusCodes); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username": "bob", "password": "wrong"}`))
+				rec := httptest.NewRecorder()
+				loginHandler.Login(rec, req)
+				statusCodes <- rec.Result().StatusCode
+			}()
+		}
+		wg.Wait()
+		close(statusCodes)
+
+		attempts := 0
+		for statusCode := range statusCodes {
+			switch statusCode {
+			case http.StatusUnauthorized:
+				attempts++
+			case http.StatusTooManyRequests:
+			default:
+				t.Errorf("expected status code as 401 or 429, got %d", statusCode)
+			}
+		}
+		if attempts > loginSettings.MaxFailures {
+			t.Errorf("expected at most %d passwords to be checked, got %d", loginSettings.MaxFailures, attempts)
+		}
+	})
+}
+
+// TestRefreshToken tests RefreshToken and RevokeToken handlers
+func TestRefreshToken(t *testing.T) {
+
+	loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, testKeyring, loginSettings)
+
+	serve := func(handlerFunc http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handlerFunc(rec, req)
+		return rec
+	}
+	refresh := func(refreshToken string) *httptest.ResponseRecorder {
+		return serve(loginHandler.RefreshToken, `{"refresh_token": "`+refreshToken+`"}`)
+	}
+
+	login := decodeTokens(t, serve(loginHandler.Login, `{"username": "bob", "password": "bob"}`))
+
+	rec := refresh(login.RefreshToken)
+	if rec.Result().StatusCode != http.StatusCreated {
+		t.Fatalf("expected status code as 201, got %d", rec.Result().StatusCode)
+	}
+	rotated := decodeTokens(t, rec)
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Fatalf("expected refresh token to be rotated")
+	}
+	if claims, err := models.VerifyAuthToken(rotated.Token, testKeyring, nil); err != nil || claims.Role != "member" {
+		t.Fatalf("expected auth token of member, got %v, %v", claims, err)
+	}
+
+	t.Run("reuse revokes rotated tokens", func(t *testing.T) {
+		if rec := refresh(login.RefreshToken); rec.Result().StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected status code as 401 on reuse, got %d", rec.Result().StatusCode)
+		}
+		if rec := refresh(rotated.RefreshToken); rec.Result().StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected status code as 401 for token of revoked family, got %d", rec.Result().StatusCode)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		tokens := decodeTokens(t, serve(loginHandler.Login, `{"username": "alice", "password": "alice"}`))
+
+		if rec := serve(loginHandler.RevokeToken, `{"refresh_token": "`+tokens.RefreshToken+`"}`); rec.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code as 200, got %d", rec.Result().StatusCode)
+		}
+		if rec := refresh(tokens.RefreshToken); rec.Result().StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected status code as 401 for revoked token, got %d", rec.Result().StatusCode)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		if rec := serve(loginHandler.RefreshToken, `{}`); rec.Result().StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status code as 400, got %d", rec.Result().StatusCode)
+		}
+	})
+}
+
+// TestLogout tests Logout handler
+func TestLogout(t *testing.T) {
+
+	revoked, err := store.NewRevocationList(nil)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), revoked, testKeyring, loginSettings)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", loginHandler.Login)
+	mux.HandleFunc("POST /token/refresh", loginHandler.RefreshToken)
+	mux.HandleFunc("POST /logout", middleware.AuthMiddleware(loginHandler.Logout, testKeyring, revoked))
+	mux.HandleFunc("GET /api/v1/objects/{id}", middleware.AuthMiddleware(handler.NewObjHandler(MockStore{}).GetObjByID, testKeyring, revoked))
+
+	serve := func(method string, target string, token string, body string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Result().StatusCode
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username": "bob", "password": "bob"}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	tokens := decodeTokens(t, rec)
+
+	if status := serve(http.MethodGet, "/api/v1/objects/1", tokens.Token, ""); status != http.StatusOK {
+		t.Fatalf("expected status code as 200 before logout, got %d", status)
+	}
+
+	if status := serve(http.MethodPost, "/logout", tokens.Token, `{"refresh_token": "`+tokens.RefreshToken+`"}`); status != http.StatusOK {
+		t.Fatalf("expected status code as 200, got %d", status)
+	}
+
+	if status := serve(http.MethodGet, "/api/v1/objects/1", tokens.Token, ""); status != http.StatusUnauthorized {
+		t.Errorf("expected status code as 401 after logout, got %d", status)
+	}
+	if status := serve(http.MethodPost, "/token/refresh", "", `{"refresh_token": "`+tokens.RefreshToken+`"}`); status != http.StatusUnauthorized {
+		t.Errorf("expected refresh token to be revoked on logout, got %d", status)
+	}
+	if status := serve(http.MethodPost, "/logout", "", ""); status != http.StatusUnauthorized {
+		t.Errorf("expected status code as 401 without token, got %d", status)
+	}
+}
+
+// writePEM writes a private key to a PEM file in PKCS #8 form
+func writePEM(t *testing.T, privateKey interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	return path
+}
+
+// TestJWKS tests JWKS handler and auth tokens signed with key pairs
+func TestJWKS(t *testing.T) {
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		alg        string
+		privateKey interface{}
+		wantKty    string
+	}{
+		{models.AlgRS256, rsaKey, "RSA"},
+		{models.AlgES256, ecKey, "EC"},
+		{models.AlgEdDSA, edKey, "OKP"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.alg, func(t *testing.T) {
+			signingKey, err := models.LoadSigningKey("", tc.alg, writePEM(t, tc.privateKey))
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			keyring, _ := models.NewKeyring(signingKey)
+
+			loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, keyring, loginSettings)
+
+			rec := httptest.NewRecorder()
+			loginHandler.JWKS(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+			var keySet models.JWKSet
+			if err := json.NewDecoder(rec.Body).Decode(&keySet); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if len(keySet.Keys) != 1 || keySet.Keys[0].KeyType != tc.wantKty || keySet.Keys[0].Algorithm != tc.alg || keySet.Keys[0].KeyID != signingKey.ID {
+				t.Fatalf("unexpected key set %+v", keySet)
+			}
+
+			rec = httptest.NewRecorder()
+			loginHandler.Login(rec, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username": "bob", "password": "bob"}`)))
+			tokens := decodeTokens(t, rec)
+
+			if _, err := models.VerifyAuthToken(tokens.Token, keyring, nil); err != nil {
+				t.Errorf("unexpected error occured %v", err)
+			}
+			if _, err := models.VerifyAuthToken(tokens.Token, testKeyring, nil); err == nil {
+				t.Errorf("expected token to be rejected by another key")
+			}
+		})
+	}
+
+	t.Run("no keys for shared secret", func(t *testing.T) {
+		loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, testKeyring, loginSettings)
+
+		rec := httptest.NewRecorder()
+		loginHandler.JWKS(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+		if body := strings.TrimSpace(rec.Body.String()); body != `{"keys":[]}` {
+			t.Errorf("expected empty key set, got %s", body)
+		}
+	})
+
+	t.Run("key does not match algorithm", func(t *testing.T) {
+		if _, err := models.LoadSigningKey("", models.AlgRS256, writePEM(t, ecKey)); err == nil {
+			t.Errorf("expected error for ECDSA key used with RS256")
+		}
+	})
+}
+
+// TestKeyringRotation tests that tokens signed with a previous key remain valid once the signing key is rotated
+func TestKeyringRotation(t *testing.T) {
+
+	dir := t.TempDir()
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	os.Rename(writePEM(t, oldKey), filepath.Join(dir, "old.pem"))
+	os.Rename(writePEM(t, newKey), filepath.Join(dir, "new.pem"))
+
+	keyringPath := filepath.Join(dir, "keyring.json")
+	writeKeyring := func(content string) {
+		if err := os.WriteFile(keyringPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+	}
+
+	writeKeyring(`{"current": "old", "keys": [{"kid": "old", "alg": "EdDSA", "key_file": "old.pem"}]}`)
+	keyring, err := models.ReadKeyringFile(keyringPath)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	loginHandler := handler.NewLoginHandler(newMockUserStore(t), store.NewMemoryRefreshTokenStore(), nil, keyring, loginSettings)
+
+	login := func() string {
+		rec := httptest.NewRecorder()
+		loginHandler.Login(rec, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username": "bob", "password": "bob"}`)))
+		return decodeTokens(t, rec).Token
+	}
+	oldToken := login()
+
+	writeKeyring(`{"current": "new", "keys": [
+		{"kid": "new", "alg": "ES256", "key_file": "new.pem"},
+		{"kid": "old", "alg": "EdDSA", "key_file": "old.pem"},
+		{"kid": "shared", "alg": "HS256", "secret": "secret"}
+	]}`)
+	rotated, err := models.ReadKeyringFile(keyringPath)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	keyring.Replace(rotated)
+
+	newToken := login()
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		if _, err := models.VerifyAuthToken(token, keyring, nil); err != nil {
+			t.Errorf("expected %s token to be valid, got %v", name, err)
+		}
+	}
+	if keyring.Current().ID != "new" {
+		t.Errorf("expected to sign with key new, got %s", keyring.Current().ID)
+	}
+
+	rec := httptest.NewRecorder()
+	loginHandler.JWKS(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	var keySet models.JWKSet
+	if err := json.NewDecoder(rec.Body).Decode(&keySet); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if len(keySet.Keys) != 2 || keySet.Keys[0].KeyID != "new" || keySet.Keys[1].KeyID != "old" {
+		t.Errorf("expected public keys new and old, got %+v", keySet.Keys)
+	}
+
+	writeKeyring(`{"current": "new", "keys": [{"kid": "new", "alg": "ES256", "key_file": "new.pem"}]}`)
+	retired, err := models.ReadKeyringFile(keyringPath)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	keyring.Replace(retired)
+
+	if _, err := models.VerifyAuthToken(oldToken, keyring, nil); err == nil {
+		t.Errorf("expected token of retired key to be rejected")
+	}
+
+	writeKeyring(`{"current": "missing", "keys": [{"kid": "new", "alg": "ES256", "key_file": "new.pem"}]}`)
+	if _, err := models.ReadKeyringFile(keyringPath); err == nil {
+		t.Errorf("expected error for keyring without current key")
+	}
+}
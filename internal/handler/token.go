@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/middleware"
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// RefreshToken exchanges a refresh token for a new auth token and a new refresh token, the exchanged refresh token cannot be used again
+func (h *LoginHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+
+	var payload models.RefreshPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.RefreshToken == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "refresh_token is required"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	nextToken, nextHash, err := models.GenerateRefreshToken()
+	if err != nil {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not refresh token. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	hash := models.HashRefreshToken(payload.RefreshToken)
+	previous, err := h.refreshTokens.RotateRefreshToken(ctxWithTimeout, hash, nextHash, time.Now().Add(h.settings.RefreshTokenTTL))
+	if err != nil {
+		log.Println(err)
+		if errors.Is(err, store.ErrRefreshTokenInvalid) {
+			if err := models.SendError(w, r, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Invalid or expired refresh token"); err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not refresh token. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	// role and tenant are read again, so that changes to the user apply from the next refresh
+	user, err := h.users.GetUser(ctxWithTimeout, previous.Username)
+	if err != nil {
+		log.Println(err)
+		if err := h.refreshTokens.RevokeRefreshToken(ctxWithTimeout, nextHash); err != nil {
+			log.Println(err)
+		}
+		if err := models.SendError(w, r, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Invalid or expired refresh token"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	h.sendTokens(w, r, user, nextToken, "Successfully refreshed token")
+}
+
+// RevokeToken revokes a refresh token along with every token it has been rotated from or to.
+// Unknown tokens are not reported, as there is nothing left to revoke.
+func (h *LoginHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+
+	var payload models.RefreshPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.RefreshToken == "" {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "refresh_token is required"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	if err := h.refreshTokens.RevokeRefreshToken(ctxWithTimeout, models.HashRefreshToken(payload.RefreshToken)); err != nil && !errors.Is(err, store.ErrRefreshTokenInvalid) {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not revoke token. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully revoked token", nil); err != nil {
+		log.Println(err)
+	}
+}
+
+// Logout revokes the auth token of the request until it expires, along with the refresh token sent in the body, if any
+func (h *LoginHandler) Logout(w http.ResponseWriter, r *http.Request) {
+
+	claims, ok := r.Context().Value(middleware.AuthClaims).(*models.CustomClaims)
+	if !ok || claims.ExpiresAt == nil {
+		if err := models.SendError(w, r, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Authentication required"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	var payload models.RefreshPayload
+
+	// the body is optional, only a malformed body is rejected
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		if err := models.SendError(w, r, http.StatusBadRequest, models.ErrCodeInvalidPayload, "Could not logout, invalid payload provided"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	defer r.Body.Close()
+
+	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	if err := h.revoked.Revoke(claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Println(err)
+		if err := models.SendError(w, r, http.StatusInternalServerError, models.ErrCodeInternal, "could not logout. Try again later"); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if payload.RefreshToken != "" {
+		if err := h.refreshTokens.RevokeRefreshToken(ctxWithTimeout, models.HashRefreshToken(payload.RefreshToken)); err != nil && !errors.Is(err, store.ErrRefreshTokenInvalid) {
+			log.Println(err)
+		}
+	}
+
+	if err := models.SendResponse(w, http.StatusOK, "Successfully logged out", nil); err != nil {
+		log.Println(err)
+	}
+}
+
+// JWKS publishes the public keys that verify auth tokens, so that other services can verify them without the signing keys.
+// Keys that sign with a shared secret are not published.
+func (h *LoginHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+
+	keySet := h.keyring.JWKSet()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(keySet); err != nil {
+		log.Println(err)
+	}
+}
@@ -12,39 +12,46 @@ import (
 
 type contextKey string
 
-// UserRole is a constant to be used as context key
-const UserRole contextKey = "role"
+// context keys under which the claims of an authenticated user are stored
+const (
+	UserRole   contextKey = "role"
+	UserTenant contextKey = "tenant"
+	// AuthClaims holds every claim of the auth token as *models.CustomClaims
+	AuthClaims contextKey = "claims"
+)
 
-// AuthMiddleware authenticate the user before accessing protected API routes
-func AuthMiddleware(next http.HandlerFunc, authSecretKey string) http.HandlerFunc {
+// AuthMiddleware authenticate the user before accessing protected API routes, rejecting tokens listed in revoked
+func AuthMiddleware(next http.HandlerFunc, keyring *models.Keyring, revoked models.RevocationList) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		authToken := strings.TrimSpace(r.Header.Get("Authorization"))
 
 		if authToken == "" {
-			unauthorized(w, "Missing Authorization header")
+			unauthorized(w, r, "Missing Authorization header")
 			return
 		}
 
 		if !strings.HasPrefix(authToken, "Bearer ") {
-			unauthorized(w, "Authentication required")
+			unauthorized(w, r, "Authentication required")
 			return
 		}
 
 		token := strings.TrimPrefix(authToken, "Bearer ")
 		if token == "" {
-			unauthorized(w, "Authentication required")
+			unauthorized(w, r, "Authentication required")
 			return
 		}
 
-		userrole, err := models.VerifyAuthToken(token, authSecretKey)
+		claims, err := models.VerifyAuthToken(token, keyring, revoked)
 		if err != nil {
 			log.Println(err)
-			unauthorized(w, "Invalid or expired token")
+			unauthorized(w, r, "Invalid or expired token")
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), UserRole, userrole)
+		ctx := context.WithValue(r.Context(), UserRole, claims.Role)
+		ctx = context.WithValue(ctx, UserTenant, claims.Tenant)
+		ctx = context.WithValue(ctx, AuthClaims, claims)
 		r = r.WithContext(ctx)
 
 		log.Println("successfully authenticated")
@@ -54,8 +61,8 @@ func AuthMiddleware(next http.HandlerFunc, authSecretKey string) http.HandlerFun
 
 }
 
-func unauthorized(w http.ResponseWriter, message string) {
-	if err := models.SendResponse(w, http.StatusUnauthorized, message, nil); err != nil {
+func unauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	if err := models.SendError(w, r, http.StatusUnauthorized, models.ErrCodeUnauthorized, message); err != nil {
 		log.Println(err)
 	}
 }
@@ -0,0 +1,52 @@
+// Package middleware defines different middlewares around request-response cycle
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// CacheStatusHeader is the response header that tells whether a response was served from cache
+const CacheStatusHeader = "X-Cache"
+
+// cacheStatusResponseWriter implements ResponseWriter interface to add the cache status header before the response is written
+type cacheStatusResponseWriter struct {
+	http.ResponseWriter
+	status      *store.CacheStatus
+	wroteHeader bool
+}
+
+// WriteHeader overrides built-in method
+func (cw *cacheStatusResponseWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if status := cw.status.String(); status != "" {
+			cw.Header().Set(CacheStatusHeader, status)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write overrides built-in method
+func (cw *cacheStatusResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// CacheStatusMiddleware records whether the store reads of a request were served from cache and reports it in the X-Cache response header
+func CacheStatusMiddleware(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, status := store.WithCacheStatus(r.Context())
+
+		cw := &cacheStatusResponseWriter{
+			ResponseWriter: w,
+			status:         status,
+		}
+
+		next.ServeHTTP(cw, r.WithContext(ctx))
+	})
+}
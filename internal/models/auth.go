@@ -7,29 +7,48 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-// CustomClaims embeds jwt.RegisteredClaims and add user role for jwt payload
+// ErrTokenRevoked is returned for an auth token that has been revoked before its expiry
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationList records the IDs of the auth tokens that have been revoked before their expiry
+type RevocationList interface {
+	// Revoke revokes the token with the given ID, which only needs to be remembered until the token expires
+	Revoke(tokenID string, expiresAt time.Time) error
+	// IsRevoked reports whether the token with the given ID has been revoked
+	IsRevoked(tokenID string) (bool, error)
+}
+
+// CustomClaims embeds jwt.RegisteredClaims and add user role and tenant for jwt payload, the ID of the token is sent as jti claim
 type CustomClaims struct {
-	Role string `json:"role"`
+	Role   string `json:"role"`
+	Tenant string `json:"tenant,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateAuthToken creates a JWT token for authentication with user role as payload
-func GenerateAuthToken(role string, secretKey string) (string, error) {
+// GenerateAuthToken creates a JWT token for authentication, valid for ttl, with user role and tenant as payload. Tenant is left out when empty.
+// The token is signed with the current key of keyring and carries its ID as kid header.
+func GenerateAuthToken(role string, tenant string, ttl time.Duration, keyring *Keyring) (string, error) {
 
-	expiration := time.Now().Add(2 * time.Minute).UTC()
+	key := keyring.Current()
+
+	expiration := time.Now().Add(ttl).UTC()
 	claims := &CustomClaims{
-		Role: role,
+		Role:   role,
+		Tenant: tenant,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(expiration),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
 
-	signedToken, err := token.SignedString([]byte(secretKey))
+	signedToken, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", err
 	}
@@ -38,26 +57,51 @@ func GenerateAuthToken(role string, secretKey string) (string, error) {
 
 }
 
-// VerifyAuthToken validate and verify authenticity of token
-func VerifyAuthToken(token string, authSecretKey string) (string, error) {
+// VerifyAuthToken validate and verify authenticity of token, which must have been signed with the key of keyring selected by its kid header,
+// or with the default HMAC key when it has no kid header, and returns its claims. Tokens listed in revoked are rejected, revoked may be nil.
+func VerifyAuthToken(token string, keyring *Keyring, revoked RevocationList) (*CustomClaims, error) {
 
 	var parsedClaims CustomClaims
 
 	parsedToken, err := jwt.ParseWithClaims(token, &parsedClaims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, hasKid := token.Header["kid"].(string)
+		if !hasKid {
+			// tokens without kid can only have been signed by the default HMAC key
+			kid = defaultKeyID
+		}
+		key, ok := keyring.Key(kid)
+		if !ok || (!hasKid && key.Method.Alg() != AlgHS256) {
+			return nil, fmt.Errorf("unknown signing key: %v", token.Header["kid"])
+		}
+		// the algorithm is fixed by the key, so that a token cannot select how it is verified
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(authSecretKey), nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
-		return "", fmt.Errorf("%w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
 
 	if !parsedToken.Valid {
-		return "", errors.New("token not valid")
+		return nil, errors.New("token not valid")
+	}
+
+	if parsedClaims.ID == "" {
+		return nil, errors.New("token not valid, jti claim is missing")
+	}
+
+	if revoked != nil {
+		isRevoked, err := revoked.IsRevoked(parsedClaims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error checking revocation of token, %w", err)
+		}
+		if isRevoked {
+			return nil, ErrTokenRevoked
+		}
 	}
 
-	return parsedClaims.Role, nil
+	return &parsedClaims, nil
 
 }
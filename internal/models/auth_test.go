@@ -0,0 +1,95 @@
+package models_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// writePEM writes a PEM block to a file and returns its path
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	return path
+}
+
+// signToken signs a token with method and key, setting kid header when it is not empty
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	token := jwt.NewWithClaims(method, &models.CustomClaims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token-id",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	return signedToken
+}
+
+// TestVerifyAuthToken tests the selection of the key that verifies an auth token
+func TestVerifyAuthToken(t *testing.T) {
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	rsaSigningKey, err := models.LoadSigningKey("rsa", models.AlgRS256, writePEM(t, "PRIVATE KEY", der))
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	defaultKey, _ := models.NewHMACKey("", "secret")
+
+	withDefaultKey, _ := models.NewKeyring(rsaSigningKey, defaultKey)
+	withoutDefaultKey, _ := models.NewKeyring(rsaSigningKey)
+
+	tests := []struct {
+		name    string
+		token   string
+		keyring *models.Keyring
+		wantErr bool
+	}{
+		{"kid selects key", signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey), withoutDefaultKey, false},
+		{"missing kid uses default key", signToken(t, jwt.SigningMethodHS256, "", []byte("secret")), withDefaultKey, false},
+		{"missing kid without default key", signToken(t, jwt.SigningMethodHS256, "", []byte("secret")), withoutDefaultKey, true},
+		{"missing kid signed with key pair", signToken(t, jwt.SigningMethodRS256, "", rsaKey), withDefaultKey, true},
+		{"unknown kid", signToken(t, jwt.SigningMethodHS256, "other", []byte("secret")), withDefaultKey, true},
+		{"HS256 signed with public key under RSA kid", signToken(t, jwt.SigningMethodHS256, "rsa", publicPEM), withDefaultKey, true},
+		{"HS256 signed with public key DER under RSA kid", signToken(t, jwt.SigningMethodHS256, "rsa", publicDER), withDefaultKey, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := models.VerifyAuthToken(tc.token, tc.keyring, nil)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected token to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if claims.Role != "admin" {
+				t.Errorf("expected role as admin, got %s", claims.Role)
+			}
+		})
+	}
+}
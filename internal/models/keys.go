@@ -0,0 +1,320 @@
+package models
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// algorithms supported to sign auth tokens
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// defaultKeyID is the ID of an HMAC key created without one, which also verifies the tokens signed before keys had IDs
+const defaultKeyID = "default"
+
+// SigningKey holds a key that signs and verifies auth tokens, along with the ID sent as kid header of the tokens it signs
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	// signKey and verifyKey are the same secret for HMAC, and the private and public key of a key pair otherwise
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// JWK represents the public part of a signing key as a JSON Web Key defined in RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKSet represents a list of JSON Web Keys as published at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKey creates a key that signs auth tokens with HS256 using a shared secret
+func NewHMACKey(keyID string, secret string) (*SigningKey, error) {
+
+	if secret == "" {
+		return nil, errors.New("HMAC secret is empty")
+	}
+	if keyID == "" {
+		keyID = defaultKeyID
+	}
+
+	return &SigningKey{
+		ID:        keyID,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}, nil
+}
+
+// LoadSigningKey reads the PEM encoded private key of a key pair used with alg, which is RS256, ES256 or EdDSA.
+// The ID of the key is derived from its public key when keyID is empty.
+func LoadSigningKey(keyID string, alg string, path string) (*SigningKey, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing key, %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("error decoding signing key %s, no PEM block found", path)
+	}
+
+	privateKey, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signing key %s, %w", path, err)
+	}
+
+	key := &SigningKey{ID: keyID, signKey: privateKey}
+
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if alg != AlgRS256 {
+			return nil, fmt.Errorf("signing key %s is an RSA key, which cannot be used with %s", path, alg)
+		}
+		key.Method, key.verifyKey = jwt.SigningMethodRS256, &k.PublicKey
+	case *ecdsa.PrivateKey:
+		if alg != AlgES256 || k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("signing key %s is an ECDSA key, which can only be used with ES256 on curve P-256", path)
+		}
+		key.Method, key.verifyKey = jwt.SigningMethodES256, &k.PublicKey
+	case ed25519.PrivateKey:
+		if alg != AlgEdDSA {
+			return nil, fmt.Errorf("signing key %s is an Ed25519 key, which cannot be used with %s", path, alg)
+		}
+		key.Method, key.verifyKey = jwt.SigningMethodEdDSA, k.Public()
+	default:
+		return nil, fmt.Errorf("signing key %s has unsupported type %T", path, privateKey)
+	}
+
+	if key.ID == "" {
+		der, err := x509.MarshalPKIXPublicKey(key.verifyKey)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving ID of signing key %s, %w", path, err)
+		}
+		sum := sha256.Sum256(der)
+		key.ID = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+
+	return key, nil
+}
+
+// parsePrivateKey parses the private key held by a PKCS #8, PKCS #1 or SEC 1 PEM block
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+}
+
+// JWK returns the public key as a JSON Web Key, it reports false for HMAC keys as they have no public part
+func (k *SigningKey) JWK() (JWK, bool) {
+
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Method.Alg(),
+	}
+
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = publicKey.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
+
+// Keyring holds the key that signs auth tokens along with previous keys that only verify the tokens they have signed,
+// so that the signing key can be rotated without rejecting outstanding tokens. It is safe for concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	current *SigningKey
+	keys    map[string]*SigningKey
+}
+
+// NewKeyring creates a keyring that signs with current and verifies with current and previous, every key must have a distinct ID
+func NewKeyring(current *SigningKey, previous ...*SigningKey) (*Keyring, error) {
+
+	keys := map[string]*SigningKey{current.ID: current}
+	for _, key := range previous {
+		if _, ok := keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key ID %q", key.ID)
+		}
+		keys[key.ID] = key
+	}
+
+	return &Keyring{
+		current: current,
+		keys:    keys,
+	}, nil
+}
+
+// Current returns the key that signs auth tokens
+func (k *Keyring) Current() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// Key returns the key with the given ID
+func (k *Keyring) Key(keyID string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[keyID]
+	return key, ok
+}
+
+// Replace swaps the keys of the keyring with the keys of other, which takes effect for every token signed or verified afterwards
+func (k *Keyring) Replace(other *Keyring) {
+
+	other.mu.RLock()
+	current, keys := other.current, other.keys
+	other.mu.RUnlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.current, k.keys = current, keys
+}
+
+// JWKSet returns the public keys of the keyring, with the current key first. HMAC keys are left out as they have no public part.
+func (k *Keyring) JWKSet() JWKSet {
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keySet := JWKSet{Keys: []JWK{}}
+	if jwk, ok := k.current.JWK(); ok {
+		keySet.Keys = append(keySet.Keys, jwk)
+	}
+
+	previousIDs := make([]string, 0, len(k.keys))
+	for keyID := range k.keys {
+		if keyID != k.current.ID {
+			previousIDs = append(previousIDs, keyID)
+		}
+	}
+	sort.Strings(previousIDs)
+
+	for _, keyID := range previousIDs {
+		if jwk, ok := k.keys[keyID].JWK(); ok {
+			keySet.Keys = append(keySet.Keys, jwk)
+		}
+	}
+
+	return keySet
+}
+
+// keyringFile represents strucutre of a keyring file, which lists every key along with the ID of the current key
+type keyringFile struct {
+	Current string `json:"current"`
+	Keys    []struct {
+		ID      string `json:"kid"`
+		Alg     string `json:"alg"`
+		KeyFile string `json:"key_file,omitempty"`
+		Secret  string `json:"secret,omitempty"`
+	} `json:"keys"`
+}
+
+// ReadKeyringFile reads a keyring from a JSON file. Each key has a kid and an alg along with a secret for HS256
+// or the path of a PEM encoded private key, which is relative to the keyring file, for RS256, ES256 and EdDSA.
+func ReadKeyringFile(path string) (*Keyring, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keyring file, %w", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("error decoding keyring file %s, %w", path, err)
+	}
+
+	var current *SigningKey
+	var previous []*SigningKey
+
+	for _, entry := range file.Keys {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("invalid keyring file %s, kid is missing", path)
+		}
+
+		var key *SigningKey
+		if entry.Alg == AlgHS256 {
+			key, err = NewHMACKey(entry.ID, entry.Secret)
+		} else {
+			keyFile := entry.KeyFile
+			if !filepath.IsAbs(keyFile) {
+				keyFile = filepath.Join(filepath.Dir(path), keyFile)
+			}
+			key, err = LoadSigningKey(entry.ID, entry.Alg, keyFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in keyring file %s, %w", entry.ID, path, err)
+		}
+
+		if entry.ID == file.Current {
+			if current != nil {
+				return nil, fmt.Errorf("invalid keyring file %s, current key %q is listed more than once", path, file.Current)
+			}
+			current = key
+		} else {
+			previous = append(previous, key)
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("invalid keyring file %s, current key %q is not listed", path, file.Current)
+	}
+
+	return NewKeyring(current, previous...)
+}
@@ -0,0 +1,136 @@
+package models_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// TestLoadSigningKey tests the errors of LoadSigningKey for keys that cannot sign auth tokens
+func TestLoadSigningKey(t *testing.T) {
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	pkcs8 := func(privateKey interface{}) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		return der
+	}
+	sec1, _ := x509.MarshalECPrivateKey(p256Key)
+	p384Sec1, _ := x509.MarshalECPrivateKey(p384Key)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		alg     string
+		path    string
+		wantErr bool
+	}{
+		{"PKCS #1 RSA key", models.AlgRS256, writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), false},
+		{"SEC 1 P-256 key", models.AlgES256, writePEM(t, "EC PRIVATE KEY", sec1), false},
+		{"PKCS #8 Ed25519 key", models.AlgEdDSA, writePEM(t, "PRIVATE KEY", pkcs8(edKey)), false},
+		{"missing file", models.AlgRS256, filepath.Join(t.TempDir(), "missing.pem"), true},
+		{"no PEM block", models.AlgRS256, notPEM, true},
+		{"unsupported PEM block", models.AlgRS256, writePEM(t, "PUBLIC KEY", publicDER), true},
+		{"malformed key", models.AlgRS256, writePEM(t, "RSA PRIVATE KEY", []byte("not a key")), true},
+		{"block type does not match key", models.AlgRS256, writePEM(t, "EC PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), true},
+		{"wrong curve", models.AlgES256, writePEM(t, "EC PRIVATE KEY", p384Sec1), true},
+		{"wrong curve in PKCS #8", models.AlgES256, writePEM(t, "PRIVATE KEY", pkcs8(p384Key)), true},
+		{"RSA key with EdDSA", models.AlgEdDSA, writePEM(t, "PRIVATE KEY", pkcs8(rsaKey)), true},
+		{"Ed25519 key with ES256", models.AlgES256, writePEM(t, "PRIVATE KEY", pkcs8(edKey)), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := models.LoadSigningKey("", tc.alg, tc.path)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error loading key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if key.ID == "" || key.Method.Alg() != tc.alg {
+				t.Errorf("expected key ID to be derived and algorithm as %s, got %q %s", tc.alg, key.ID, key.Method.Alg())
+			}
+		})
+	}
+}
+
+// TestReadKeyringFile tests the errors of ReadKeyringFile for invalid keyring files
+func TestReadKeyringFile(t *testing.T) {
+
+	dir := t.TempDir()
+	edDER, _ := x509.MarshalPKCS8PrivateKey(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	if err := os.Rename(writePEM(t, "PRIVATE KEY", edDER), filepath.Join(dir, "ed.pem")); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid keyring", `{"current": "new", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "ed.pem"}, {"kid": "old", "alg": "HS256", "secret": "secret"}]}`, false},
+		{"invalid JSON", `{"current": "new", "keys": [`, true},
+		{"missing kid", `{"current": "new", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "ed.pem"}, {"alg": "HS256", "secret": "secret"}]}`, true},
+		{"current key not listed", `{"current": "missing", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "ed.pem"}]}`, true},
+		{"duplicate current kid", `{"current": "new", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "ed.pem"}, {"kid": "new", "alg": "HS256", "secret": "secret"}]}`, true},
+		{"duplicate previous kid", `{"current": "new", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "ed.pem"}, {"kid": "old", "alg": "HS256", "secret": "a"}, {"kid": "old", "alg": "HS256", "secret": "b"}]}`, true},
+		{"missing secret", `{"current": "new", "keys": [{"kid": "new", "alg": "HS256"}]}`, true},
+		{"missing key file", `{"current": "new", "keys": [{"kid": "new", "alg": "EdDSA", "key_file": "missing.pem"}]}`, true},
+		{"key does not match algorithm", `{"current": "new", "keys": [{"kid": "new", "alg": "RS256", "key_file": "ed.pem"}]}`, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "keyring.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+
+			keyring, err := models.ReadKeyringFile(path)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error reading keyring file")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if keyring.Current().ID != "new" || keyring.Current().Method.Alg() != models.AlgEdDSA {
+				t.Errorf("expected current key as new, got %s", keyring.Current().ID)
+			}
+			if _, ok := keyring.Key("old"); !ok {
+				t.Errorf("expected previous key to be listed")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := models.ReadKeyringFile(filepath.Join(dir, "missing.json")); err == nil {
+			t.Errorf("expected error reading keyring file")
+		}
+	})
+}
@@ -28,10 +28,24 @@ type ObjDataPayload struct {
 	Data map[string]interface{} `json:"data,omitempty"`
 }
 
-// NewObj represents strucutre of an object that has newly been created
+// NewObj represents strucutre of an object that has newly been created or updated
 type NewObj struct {
 	ID        string                 `json:"id"`
-	CreatedAt string                 `json:"createdAt"`
+	CreatedAt string                 `json:"createdAt,omitempty"`
+	UpdatedAt string                 `json:"updatedAt,omitempty"`
 	Name      string                 `json:"name"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 }
+
+// DeleteResult represents the outcome of deleting an object
+type DeleteResult struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// BatchLookupResult represents the objects retrieved for a list of requested IDs along with the IDs that were not found.
+// Objects holds the retrieved objects, or only their requested fields when a sparse fieldset is requested.
+type BatchLookupResult struct {
+	Objects    interface{} `json:"objects"`
+	MissingIDs []string    `json:"missing_ids"`
+}
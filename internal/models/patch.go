@@ -0,0 +1,33 @@
+package models
+
+// MergePatchContentType is the media type of a JSON Merge Patch document as defined in RFC 7386
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatch applies a JSON Merge Patch document on target and returns the result as a new map.
+// Keys set to null in the patch are removed from the result, nested objects are merged recursively
+// and every other value replaces the one present in target. The target map is never modified.
+func MergePatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+
+	result := make(map[string]interface{}, len(target)+len(patch))
+	for key, value := range target {
+		result[key] = value
+	}
+
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(result, key)
+			continue
+		}
+
+		patchObj, ok := patchValue.(map[string]interface{})
+		if !ok {
+			result[key] = patchValue
+			continue
+		}
+
+		targetObj, _ := result[key].(map[string]interface{})
+		result[key] = MergePatch(targetObj, patchObj)
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of an error response as defined in RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// machine-readable codes sent along with every error response
+const (
+	ErrCodeUnauthorized         = "unauthorized"
+	ErrCodeForbidden            = "forbidden"
+	ErrCodeAccountLocked        = "account_locked"
+	ErrCodeInvalidPayload       = "invalid_payload"
+	ErrCodeInvalidQuery         = "invalid_query"
+	ErrCodeMissingID            = "missing_object_id"
+	ErrCodeUnsupportedMediaType = "unsupported_media_type"
+	ErrCodeNotFound             = "object_not_found"
+	ErrCodeConflict             = "object_conflict"
+	ErrCodeUpstreamUnavailable  = "upstream_unavailable"
+	ErrCodeUpstreamTimeout      = "upstream_timeout"
+	ErrCodeServiceUnavailable   = "service_unavailable"
+	ErrCodeInternal             = "internal_error"
+)
+
+// Problem defines the strucutre of an error response as defined in RFC 7807, extended with a machine-readable error code
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// SendError constructs the error response to be sent for the request.
+// Clients that accept application/problem+json receive a Problem, every other client receives a Response.
+func SendError(w http.ResponseWriter, r *http.Request, code int, errCode string, detail string) error {
+
+	if !AcceptsProblem(r) {
+		return SendResponse(w, code, detail, nil)
+	}
+
+	problemToSend := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     errCode,
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(problemToSend); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"type": "about:blank", "title": "Internal Server Error", "status": 500, "code": "internal_error"}`))
+		return fmt.Errorf("error sending error response, %w", err)
+	}
+
+	w.WriteHeader(code)
+	buf.WriteTo(w)
+
+	return nil
+
+}
+
+// AcceptsProblem reports whether the Accept header of the request lists application/problem+json
+func AcceptsProblem(r *http.Request) bool {
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+
+	return false
+}
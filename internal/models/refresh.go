@@ -0,0 +1,51 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// RefreshToken represents the server-side record of a refresh token, which is only kept as a hash.
+// Tokens rotated from one another share a family, so that all of them can be revoked together.
+type RefreshToken struct {
+	Hash      string
+	Family    string
+	Username  string
+	ExpiresAt time.Time
+	// Used is set once the token has been exchanged, a used token is only kept to detect its reuse
+	Used bool
+}
+
+// TokenPair represents strucutre of the tokens sent on login and refresh, ExpiresIn is the lifetime of Token in seconds
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// RefreshPayload represents strucutre of the refresh token sent to renew or revoke it
+type RefreshPayload struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// GenerateRefreshToken creates an opaque refresh token along with the hash under which it is stored
+func GenerateRefreshToken() (string, string, error) {
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", fmt.Errorf("error generating refresh token, %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hash under which a refresh token is stored
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
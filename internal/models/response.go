@@ -9,22 +9,49 @@ import (
 
 // Response defines the strucutre of response data that will be sent for a request
 type Response struct {
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Status     string      `json:"status"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination defines the metadata sent along with a page of a list
+type Pagination struct {
+	TotalCount    int    `json:"total_count"`
+	NextPageToken string `json:"next_page_token,omitempty"`
 }
 
 // SendResponse constructs the response to be sent for the request
 func SendResponse(w http.ResponseWriter, code int, message string, data interface{}) error {
 
-	w.Header().Set("Content-Type", "application/json")
-
 	responseToSend := &Response{
 		Status:  http.StatusText(code),
 		Message: message,
 		Data:    data,
 	}
 
+	return writeResponse(w, code, responseToSend)
+
+}
+
+// SendPaginatedResponse constructs the response to be sent for the request along with the pagination metadata of data
+func SendPaginatedResponse(w http.ResponseWriter, code int, message string, data interface{}, pagination *Pagination) error {
+
+	responseToSend := &Response{
+		Status:     http.StatusText(code),
+		Message:    message,
+		Data:       data,
+		Pagination: pagination,
+	}
+
+	return writeResponse(w, code, responseToSend)
+
+}
+
+func writeResponse(w http.ResponseWriter, code int, responseToSend *Response) error {
+
+	w.Header().Set("Content-Type", "application/json")
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(responseToSend); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,15 @@
+package models
+
+// User represents strucutre of a user that is allowed to login, along with the role and tenant granted to it
+type User struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	Role         string `json:"role"`
+	Tenant       string `json:"tenant,omitempty"`
+}
+
+// LoginPayload represents strucutre of the credentials sent to login
+type LoginPayload struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,225 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// operators supported by a filter, `eq` is used when no operator is provided
+const (
+	OpEqual          = "eq"
+	OpNotEqual       = "ne"
+	OpGreater        = "gt"
+	OpGreaterOrEqual = "gte"
+	OpLess           = "lt"
+	OpLessOrEqual    = "lte"
+	OpContains       = "contains"
+)
+
+var (
+	// filterParamRegex matches query params like filter[data.price][gte] and filter[name]
+	filterParamRegex = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+	// leadingNumberRegex matches the number a text starts with, such as 64 in "64 GB"
+	leadingNumberRegex = regexp.MustCompile(`^\s*([-+]?\d+(?:\.\d+)?)`)
+)
+
+// Filter represents a condition on a field of an object that must hold for the object to be selected
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// ParseFilters parses filters from query params in the form filter[<field>][<operator>]=<value>.
+// A field is one of `id`, `name` or `data.<key>`, and the operator defaults to `eq` when omitted.
+func ParseFilters(params url.Values) ([]Filter, error) {
+
+	var filters []Filter
+	for param, values := range params {
+		// only params in the filter syntax are filters, other params such as `filters` are ignored like any unknown param
+		if !strings.HasPrefix(param, "filter[") {
+			continue
+		}
+
+		matches := filterParamRegex.FindStringSubmatch(param)
+		if matches == nil {
+			return nil, fmt.Errorf("malformed filter %q", param)
+		}
+
+		field, operator := matches[1], matches[2]
+		if operator == "" {
+			operator = OpEqual
+		}
+
+		if err := validateField(field); err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case OpEqual, OpNotEqual, OpGreater, OpGreaterOrEqual, OpLess, OpLessOrEqual, OpContains:
+		default:
+			return nil, fmt.Errorf("unknown filter operator %q", operator)
+		}
+
+		for _, value := range values {
+			filters = append(filters, Filter{Field: field, Operator: operator, Value: value})
+		}
+	}
+
+	// keep filters in a predictable order as query params are not ordered
+	sort.Slice(filters, func(i, j int) bool {
+		if filters[i].Field != filters[j].Field {
+			return filters[i].Field < filters[j].Field
+		}
+		if filters[i].Operator != filters[j].Operator {
+			return filters[i].Operator < filters[j].Operator
+		}
+		return filters[i].Value < filters[j].Value
+	})
+
+	return filters, nil
+}
+
+// ApplyFilters returns the objects that satisfy every filter
+func ApplyFilters(objects []models.ObjDataFromResponse, filters []Filter) []models.ObjDataFromResponse {
+
+	if len(filters) == 0 {
+		return objects
+	}
+
+	results := make([]models.ObjDataFromResponse, 0, len(objects))
+	for _, obj := range objects {
+		selected := true
+		for _, filter := range filters {
+			if !filter.Match(obj) {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			results = append(results, obj)
+		}
+	}
+
+	return results
+}
+
+// Match reports whether the object satisfies the filter. An object that does not have the field never matches.
+func (f Filter) Match(obj models.ObjDataFromResponse) bool {
+
+	value, ok := FieldValue(obj, f.Field)
+	if !ok {
+		return false
+	}
+
+	switch f.Operator {
+	case OpEqual:
+		return equalValue(value, f.Value)
+	case OpNotEqual:
+		return !equalValue(value, f.Value)
+	case OpContains:
+		return strings.Contains(strings.ToLower(fmt.Sprint(value)), strings.ToLower(f.Value))
+	}
+
+	cmp := compareValue(value, f.Value)
+	switch f.Operator {
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterOrEqual:
+		return cmp >= 0
+	case OpLess:
+		return cmp < 0
+	case OpLessOrEqual:
+		return cmp <= 0
+	}
+
+	return false
+}
+
+// FieldValue returns the value of a field of an object, where field is one of `id`, `name` or `data.<key>`
+func FieldValue(obj models.ObjDataFromResponse, field string) (interface{}, bool) {
+
+	switch field {
+	case "id":
+		return obj.ID, true
+	case "name":
+		return obj.Name, true
+	}
+
+	return DataValue(obj.Data, strings.TrimPrefix(field, "data."))
+}
+
+// validateField checks that field refers to a field that can be read using FieldValue
+func validateField(field string) error {
+	if field == "id" || field == "name" || (strings.HasPrefix(field, "data.") && len(field) > len("data.")) {
+		return nil
+	}
+	return fmt.Errorf("unknown field %q, use id, name or data.<key>", field)
+}
+
+// equalValue compares numbers numerically, booleans logically and everything else as case-insensitive text
+func equalValue(value interface{}, expected string) bool {
+
+	switch v := value.(type) {
+	case nil:
+		return expected == "null"
+	case float64:
+		if expectedNum, err := strconv.ParseFloat(expected, 64); err == nil {
+			return v == expectedNum
+		}
+	case bool:
+		if expectedBool, err := strconv.ParseBool(expected); err == nil {
+			return v == expectedBool
+		}
+	}
+
+	return strings.EqualFold(fmt.Sprint(value), expected)
+}
+
+// compareValue compares value with expected numerically when both are numbers or texts starting with a number,
+// such as "64 GB", and as case-insensitive text otherwise
+func compareValue(value interface{}, expected string) int {
+
+	num, numOK := numericValue(value)
+	expectedNum, expectedNumOK := numericValue(expected)
+	if numOK && expectedNumOK {
+		switch {
+		case num < expectedNum:
+			return -1
+		case num > expectedNum:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(fmt.Sprint(value)), strings.ToLower(expected))
+}
+
+// numericValue returns the number held by a value decoded from JSON
+func numericValue(value interface{}) (float64, bool) {
+
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case string:
+		matches := leadingNumberRegex.FindStringSubmatch(v)
+		if matches == nil {
+			return 0, false
+		}
+		num, err := strconv.ParseFloat(matches[1], 64)
+		return num, err == nil
+	}
+
+	return 0, false
+}
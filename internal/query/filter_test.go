@@ -0,0 +1,73 @@
+package query_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/query"
+)
+
+// TestParseFilters tests ParseFilters
+func TestParseFilters(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		wantErr  bool
+		wantLen  int
+	}{
+		{"no filters", "sort=name&limit=2", false, 0},
+		{"default operator", "filter[data.color]=Silver", false, 1},
+		{"explicit operators", "filter[data.price][gte]=500&filter[data.price][lt]=2000", false, 2},
+		{"unknown operator", "filter[data.price][between]=1", true, 0},
+		{"unknown field", "filter[price]=1", true, 0},
+		{"malformed filter", "filter[data.price", true, 0},
+		{"params only starting with filter are ignored", "filters=x&filtering=1", false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params, _ := url.ParseQuery(tc.rawQuery)
+			filters, err := query.ParseFilters(params)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if len(filters) != tc.wantLen {
+				t.Errorf("expected %d filters, got %d", tc.wantLen, len(filters))
+			}
+		})
+	}
+}
+
+// TestApplyFilters tests ApplyFilters
+func TestApplyFilters(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		wantIDs  []string
+	}{
+		{"numeric comparison", "filter[data.price][gte]=500", []string{"7"}},
+		{"number equality", "filter[data.year]=2019", []string{"7"}},
+		{"case-insensitive text equality", "filter[data.color]=cloudy white", []string{"1", "3"}},
+		{"text starting with number", "filter[data.capacity][lte]=100", []string{"10"}},
+		{"not equal", "filter[data.color][ne]=Cloudy White&filter[data.capacity GB][gt]=0", nil},
+		{"contains on name", "filter[name][contains]=iphone", []string{"2", "3"}},
+		{"combined filters", "filter[name][contains]=apple&filter[data.color]=Cloudy White", []string{"3"}},
+		{"missing key never matches", "filter[data.strap][ne]=Black", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params, _ := url.ParseQuery(tc.rawQuery)
+			filters, err := query.ParseFilters(params)
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			results := query.ApplyFilters(testObjects(), filters)
+			if got := ids(results); !equalIDs(got, tc.wantIDs) {
+				t.Errorf("expected IDs %v, got %v", tc.wantIDs, got)
+			}
+		})
+	}
+}
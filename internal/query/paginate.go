@@ -0,0 +1,197 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// MaxPageLimit is the maximum number of objects returned in a single page
+const MaxPageLimit = 100
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded or was issued for a different listing
+var ErrInvalidPageToken = errors.New("invalid page_token")
+
+// PageRequest represents the page of objects requested by the client
+type PageRequest struct {
+	Limit     int
+	PageToken string
+}
+
+// pageToken represents the decoded content of an opaque page token, which holds the position of the last object
+// of the previous page as its values of the sort keys followed by its ID
+type pageToken struct {
+	After       []interface{} `json:"a"`
+	AfterID     string        `json:"i"`
+	Limit       int           `json:"l"`
+	Fingerprint uint64        `json:"f"`
+}
+
+// ParsePageRequest parses the `limit` and `page_token` query params. A limit of 0 means that the complete list is requested.
+func ParsePageRequest(limitParam string, pageTokenParam string) (PageRequest, error) {
+
+	page := PageRequest{PageToken: pageTokenParam}
+	if limitParam == "" {
+		return page, nil
+	}
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit < 1 || limit > MaxPageLimit {
+		return page, fmt.Errorf("limit must be a number between 1 and %d", MaxPageLimit)
+	}
+	page.Limit = limit
+
+	return page, nil
+}
+
+// Paginate orders objects and returns the requested page of them along with the pagination metadata.
+// Objects are ordered by the sort keys followed by their ID, whether they are paged or not, and a page token resumes right after the last object
+// of the previous page, so that objects created or deleted between requests do not shift the following pages.
+// The filters and sort keys used to build the list are tied to the page token, so that a token cannot be reused for a different listing.
+func Paginate(objects []models.ObjDataFromResponse, page PageRequest, filters []Filter, keys []SortKey) ([]models.ObjDataFromResponse, *models.Pagination, error) {
+
+	fingerprint := listingFingerprint(filters, keys)
+	limit := page.Limit
+
+	var token *pageToken
+	if page.PageToken != "" {
+		decoded, err := decodePageToken(page.PageToken)
+		if err != nil || decoded.Fingerprint != fingerprint || len(decoded.After) != len(keys) {
+			return nil, nil, ErrInvalidPageToken
+		}
+		token = &decoded
+		if limit == 0 {
+			limit = token.Limit
+		}
+	}
+
+	pagination := &models.Pagination{
+		TotalCount: len(objects),
+	}
+
+	// the complete list is ordered like its pages, so that paging a listing does not change its order
+	ordered := orderForPaging(objects, keys)
+	if limit == 0 {
+		return ordered, pagination, nil
+	}
+
+	start := 0
+	if token != nil {
+		start = sort.Search(len(ordered), func(i int) bool {
+			return compareToCursor(ordered[i], keys, token) > 0
+		})
+	}
+	end := start + limit
+	if end > len(ordered) {
+		end = len(ordered)
+	}
+
+	if end < len(ordered) {
+		last := ordered[end-1]
+		next := pageToken{
+			After:       make([]interface{}, len(keys)),
+			AfterID:     last.ID,
+			Limit:       limit,
+			Fingerprint: fingerprint,
+		}
+		for i, key := range keys {
+			if value, ok := FieldValue(last, key.Field); ok {
+				next.After[i] = value
+			}
+		}
+		pagination.NextPageToken = encodePageToken(next)
+	}
+
+	return ordered[start:end], pagination, nil
+}
+
+// orderForPaging returns a copy of objects ordered by the sort keys with the ID of objects breaking ties,
+// which gives every object a unique position to resume from
+func orderForPaging(objects []models.ObjDataFromResponse, keys []SortKey) []models.ObjDataFromResponse {
+
+	ordered := make([]models.ObjDataFromResponse, len(objects))
+	copy(ordered, objects)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		for _, key := range keys {
+			if cmp := compareField(ordered[i], ordered[j], key); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return compareIDs(ordered[i].ID, ordered[j].ID) < 0
+	})
+
+	return ordered
+}
+
+// compareToCursor compares the position of an object with the position held by a page token
+func compareToCursor(obj models.ObjDataFromResponse, keys []SortKey, token *pageToken) int {
+
+	for i, key := range keys {
+		value, ok := FieldValue(obj, key.Field)
+		cmp := compareValues(value, ok, token.After[i], true, key)
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return compareIDs(obj.ID, token.AfterID)
+}
+
+// compareIDs compares IDs that are whole numbers, such as the IDs of external API, numerically and places them
+// before the other IDs, which are compared as text
+func compareIDs(a string, b string) int {
+
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil && aNum < bNum:
+		return -1
+	case aErr == nil && bErr == nil && aNum > bNum:
+		return 1
+	case aErr == nil && bErr != nil:
+		return -1
+	case aErr != nil && bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func encodePageToken(token pageToken) string {
+	encoded, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodePageToken(encoded string) (pageToken, error) {
+
+	var token pageToken
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, err
+	}
+
+	if err := json.Unmarshal(decoded, &token); err != nil {
+		return token, err
+	}
+
+	if token.Limit < 1 || token.Limit > MaxPageLimit {
+		return token, ErrInvalidPageToken
+	}
+
+	return token, nil
+}
+
+// listingFingerprint identifies a listing by the filters and sort keys applied to it
+func listingFingerprint(filters []Filter, keys []SortKey) uint64 {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%v|%v", filters, keys)
+	return hash.Sum64()
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// Projection represents the fields of an object that are requested by the client
+type Projection struct {
+	id       bool
+	name     bool
+	allData  bool
+	dataKeys []string
+}
+
+// ParseFields parses a comma separated list of fields such as `id,name,data.price`, where `data` requests the complete data
+// and `data.<key>` a single key of it. An empty list results in a projection that keeps every field.
+func ParseFields(param string) (*Projection, error) {
+
+	var projection *Projection
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if projection == nil {
+			projection = &Projection{}
+		}
+
+		switch {
+		case field == "id":
+			projection.id = true
+		case field == "name":
+			projection.name = true
+		case field == "data":
+			projection.allData = true
+		case strings.HasPrefix(field, "data.") && len(field) > len("data."):
+			projection.dataKeys = append(projection.dataKeys, strings.TrimPrefix(field, "data."))
+		default:
+			return nil, fmt.Errorf("unknown field %q, use id, name, data or data.<key>", field)
+		}
+	}
+
+	return projection, nil
+}
+
+// Apply returns the object unchanged when no fields are requested, and otherwise a map holding only the requested fields,
+// so that the wire format of complete objects stays the same. Requested data keys that the object does not have are left out.
+func (p *Projection) Apply(obj models.ObjDataFromResponse) interface{} {
+
+	if p == nil {
+		return obj
+	}
+
+	return p.project(obj)
+}
+
+// ApplyAll returns the objects unchanged when no fields are requested, and otherwise a map of every object holding only the requested fields
+func (p *Projection) ApplyAll(objects []models.ObjDataFromResponse) interface{} {
+
+	if p == nil {
+		return objects
+	}
+
+	projected := make([]map[string]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		projected = append(projected, p.project(obj))
+	}
+
+	return projected
+}
+
+// project copies the requested fields of an object into a map, requested id and name are kept even when empty
+func (p *Projection) project(obj models.ObjDataFromResponse) map[string]interface{} {
+
+	projected := make(map[string]interface{}, 3)
+	if p.id {
+		projected["id"] = obj.ID
+	}
+	if p.name {
+		projected["name"] = obj.Name
+	}
+
+	if p.allData {
+		if obj.Data != nil {
+			projected["data"] = obj.Data
+		}
+		return projected
+	}
+
+	var data map[string]interface{}
+	for _, key := range p.dataKeys {
+		dataKey, ok := resolveDataKey(obj.Data, key)
+		if !ok {
+			continue
+		}
+		if data == nil {
+			data = make(map[string]interface{}, len(p.dataKeys))
+		}
+		data[dataKey] = obj.Data[dataKey]
+	}
+	if data != nil {
+		projected["data"] = data
+	}
+
+	return projected
+}
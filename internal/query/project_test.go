@@ -0,0 +1,65 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/query"
+)
+
+// TestProjection tests ParseFields and Projection.Apply
+func TestProjection(t *testing.T) {
+
+	macbook := testObjects()[3]
+
+	t.Run("no fields keeps object", func(t *testing.T) {
+		projection, err := query.ParseFields("")
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		projected, ok := projection.Apply(macbook).(models.ObjDataFromResponse)
+		if !ok || projected.ID != macbook.ID || projected.Name != macbook.Name || len(projected.Data) != len(macbook.Data) {
+			t.Errorf("expected object to be unchanged, got %v", projected)
+		}
+	})
+
+	t.Run("id and data keys", func(t *testing.T) {
+		projection, err := query.ParseFields("id, data.Price,data.cpu model,data.color")
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		projected := projection.Apply(macbook).(map[string]interface{})
+		if _, ok := projected["name"]; ok || projected["id"] != "7" {
+			t.Errorf("expected only id to be kept, got %v", projected)
+		}
+		data, _ := projected["data"].(map[string]interface{})
+		if len(data) != 2 || data["price"] != 1849.99 || data["CPU model"] != "Intel Core i9" {
+			t.Errorf("expected price and CPU model in data, got %v", data)
+		}
+		if macbook.Data["year"] != 2019.0 {
+			t.Errorf("expected original object to be unchanged, got %v", macbook.Data)
+		}
+	})
+
+	t.Run("data keys missing from object", func(t *testing.T) {
+		projection, _ := query.ParseFields("name,data.color")
+		projected := projection.Apply(macbook).(map[string]interface{})
+		if _, ok := projected["data"]; ok || len(projected) != 1 || projected["name"] != macbook.Name {
+			t.Errorf("expected only name to be kept, got %v", projected)
+		}
+	})
+
+	t.Run("requested fields kept when empty", func(t *testing.T) {
+		projection, _ := query.ParseFields("id,name")
+		projected := projection.Apply(models.ObjDataFromResponse{ID: "14"}).(map[string]interface{})
+		if name, ok := projected["name"]; !ok || name != "" {
+			t.Errorf("expected empty name to be kept, got %v", projected)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := query.ParseFields("id,price"); err == nil {
+			t.Errorf("expected error for unknown field")
+		}
+	})
+}
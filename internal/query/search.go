@@ -0,0 +1,233 @@
+// Package query defines operations that are performed on a list of objects retrieved from the store
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// weights applied to a match based on how closely the searched text matches a value
+const (
+	exactMatchScore     = 10
+	prefixMatchScore    = 6
+	substringMatchScore = 4
+	tokenMatchScore     = 2
+	partialTokenScore   = 1
+
+	// matches on the name of an object are ranked above matches on its data
+	nameWeight = 2
+)
+
+// searchTarget represents the part of an object that a search is performed on
+type searchTarget struct {
+	name    bool
+	allData bool
+	dataKey string
+}
+
+// Search returns the objects whose name or string values of data match the searched text, ranked by relevance.
+// Matching is case-insensitive and an object matches when it contains the complete text or every word of it.
+// fields restricts the search to `name`, `data` or a single data key as `data.<key>`, all of them are searched when empty.
+func Search(objects []models.ObjDataFromResponse, text string, fields []string) ([]models.ObjDataFromResponse, error) {
+
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil, fmt.Errorf("search text is missing")
+	}
+
+	targets, err := parseSearchFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := uniqueTokens(text)
+
+	type rankedObj struct {
+		obj   models.ObjDataFromResponse
+		score int
+	}
+	var ranked []rankedObj
+
+	for _, obj := range objects {
+		score, matched := 0, false
+		tokensFound := make(map[string]bool, len(tokens))
+
+		for _, target := range targets {
+			for _, value := range target.values(obj) {
+				valueScore, fullMatch := scoreValue(strings.ToLower(value), text, tokens, tokensFound)
+				if target.name {
+					valueScore *= nameWeight
+				}
+				score += valueScore
+				matched = matched || fullMatch
+			}
+		}
+
+		if matched || len(tokensFound) == len(tokens) {
+			ranked = append(ranked, rankedObj{obj: obj, score: score})
+		}
+	}
+
+	// objects with equal relevance keep the order in which they were retrieved
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	results := make([]models.ObjDataFromResponse, 0, len(ranked))
+	for _, r := range ranked {
+		results = append(results, r.obj)
+	}
+
+	return results, nil
+}
+
+// parseSearchFields converts the requested search fields into search targets
+func parseSearchFields(fields []string) ([]searchTarget, error) {
+
+	var targets []searchTarget
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "":
+			continue
+		case field == "name":
+			targets = append(targets, searchTarget{name: true})
+		case field == "data":
+			targets = append(targets, searchTarget{allData: true})
+		case strings.HasPrefix(field, "data.") && len(field) > len("data."):
+			targets = append(targets, searchTarget{dataKey: strings.TrimPrefix(field, "data.")})
+		default:
+			return nil, fmt.Errorf("unknown search field %q", field)
+		}
+	}
+
+	if len(targets) == 0 {
+		targets = []searchTarget{{name: true}, {allData: true}}
+	}
+
+	return targets, nil
+}
+
+// values returns the text values of an object that are covered by the search target
+func (t searchTarget) values(obj models.ObjDataFromResponse) []string {
+
+	if t.name {
+		return []string{obj.Name}
+	}
+
+	if t.allData {
+		var values []string
+		for _, value := range obj.Data {
+			if str, ok := value.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+
+	if value, ok := DataValue(obj.Data, t.dataKey); ok {
+		if str, ok := value.(string); ok {
+			return []string{str}
+		}
+	}
+
+	return nil
+}
+
+// scoreValue scores a lowercased value against the searched text and records which of its tokens were found.
+// It reports whether the value contains the complete searched text.
+func scoreValue(value string, text string, tokens []string, tokensFound map[string]bool) (int, bool) {
+
+	switch {
+	case value == text:
+		markTokens(tokens, tokensFound)
+		return exactMatchScore, true
+	case strings.HasPrefix(value, text):
+		markTokens(tokens, tokensFound)
+		return prefixMatchScore, true
+	case strings.Contains(value, text):
+		markTokens(tokens, tokensFound)
+		return substringMatchScore, true
+	}
+
+	score := 0
+	words := strings.FieldsFunc(value, isSeparator)
+	for _, token := range tokens {
+		switch {
+		case containsWord(words, token):
+			score += tokenMatchScore
+			tokensFound[token] = true
+		case strings.Contains(value, token):
+			score += partialTokenScore
+			tokensFound[token] = true
+		}
+	}
+
+	return score, false
+}
+
+// uniqueTokens splits the searched text into words, keeping only the first occurrence of a repeated word
+// so that every token can be counted once when checking whether all of them were found
+func uniqueTokens(text string) []string {
+
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, token := range strings.Fields(text) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+func markTokens(tokens []string, tokensFound map[string]bool) {
+	for _, token := range tokens {
+		tokensFound[token] = true
+	}
+}
+
+func containsWord(words []string, token string) bool {
+	for _, word := range words {
+		if word == token {
+			return true
+		}
+	}
+	return false
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == ',' || r == '-' || r == '/' || r == '(' || r == ')'
+}
+
+// DataValue returns the value of a key from the data of an object.
+// The key is matched exactly first and then case-insensitively, as keys of upstream objects are not consistently cased.
+func DataValue(data map[string]interface{}, key string) (interface{}, bool) {
+
+	dataKey, ok := resolveDataKey(data, key)
+	if !ok {
+		return nil, false
+	}
+
+	return data[dataKey], true
+}
+
+// resolveDataKey returns the key of data that matches the requested key, as described for DataValue
+func resolveDataKey(data map[string]interface{}, key string) (string, bool) {
+
+	if _, ok := data[key]; ok {
+		return key, true
+	}
+
+	for dataKey := range data {
+		if strings.EqualFold(dataKey, key) {
+			return dataKey, true
+		}
+	}
+
+	return "", false
+}
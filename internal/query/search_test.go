@@ -0,0 +1,88 @@
+// Package query_test tests all the functionality present in query package
+package query_test
+
+import (
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/query"
+)
+
+// testObjects returns a list of objects resembling the reserved objects of upstream API
+func testObjects() []models.ObjDataFromResponse {
+	return []models.ObjDataFromResponse{
+		{ID: "1", Name: "Google Pixel 6 Pro", Data: map[string]interface{}{"color": "Cloudy White", "capacity": "128 GB"}},
+		{ID: "2", Name: "Apple iPhone 12 Mini, 256GB, Blue", Data: nil},
+		{ID: "3", Name: "Apple iPhone 12 Pro Max", Data: map[string]interface{}{"color": "Cloudy White", "capacity GB": 512.0}},
+		{ID: "7", Name: "Apple MacBook Pro 16", Data: map[string]interface{}{"year": 2019.0, "price": 1849.99, "CPU model": "Intel Core i9"}},
+		{ID: "10", Name: "Apple iPad Mini 5th Gen", Data: map[string]interface{}{"Capacity": "64 GB", "Screen size": 7.9}},
+	}
+}
+
+func ids(objects []models.ObjDataFromResponse) []string {
+	var result []string
+	for _, obj := range objects {
+		result = append(result, obj.ID)
+	}
+	return result
+}
+
+func equalIDs(got []string, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSearch tests Search
+func TestSearch(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		text    string
+		fields  []string
+		wantIDs []string
+	}{
+		{"case-insensitive substring", "IPHONE 12", nil, []string{"2", "3"}},
+		{"data values", "white", nil, []string{"1", "3"}},
+		{"tokens in any order", "mini apple", nil, []string{"2", "10"}},
+		{"tokens across name and data", "macbook intel", nil, []string{"7"}},
+		{"repeated token", "mini apple MINI", nil, []string{"2", "10"}},
+		{"restricted to data key with space", "core i9", []string{"data.cpu model"}, []string{"7"}},
+		{"restricted to name", "white", []string{"name"}, nil},
+		{"no match", "samsung", nil, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := query.Search(testObjects(), tc.text, tc.fields)
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if got := ids(results); !equalIDs(got, tc.wantIDs) {
+				t.Errorf("expected IDs %v, got %v", tc.wantIDs, got)
+			}
+		})
+	}
+
+	t.Run("exact match ranked first", func(t *testing.T) {
+		results, err := query.Search(testObjects(), "apple iphone 12 pro max", nil)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if len(results) == 0 || results[0].ID != "3" {
+			t.Errorf("expected object 3 to be ranked first, got %v", ids(results))
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := query.Search(testObjects(), "apple", []string{"price"}); err == nil {
+			t.Errorf("expected error for unknown search field")
+		}
+	})
+}
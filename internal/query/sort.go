@@ -0,0 +1,114 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// SortKey represents a field that objects are ordered by
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort parses a comma separated list of fields such as `name,-data.price`, where a leading `-` sorts in descending order
+func ParseSort(param string) ([]SortKey, error) {
+
+	var keys []SortKey
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key := SortKey{Field: field}
+		if strings.HasPrefix(field, "-") {
+			key = SortKey{Field: strings.TrimPrefix(field, "-"), Descending: true}
+		}
+
+		if err := validateField(key.Field); err != nil {
+			return nil, fmt.Errorf("invalid sort, %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// SortObjects returns a copy of objects ordered by the sort keys. The sort is stable, so objects that are equal
+// on every key keep their original order. Objects without a value for a key, such as objects having no data,
+// are placed last irrespective of the direction.
+func SortObjects(objects []models.ObjDataFromResponse, keys []SortKey) []models.ObjDataFromResponse {
+
+	sorted := make([]models.ObjDataFromResponse, len(objects))
+	copy(sorted, objects)
+
+	if len(keys) == 0 {
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compareField(sorted[i], sorted[j], key)
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+
+	return sorted
+}
+
+// compareField compares the values of a field of two objects in the direction of the sort key
+func compareField(a models.ObjDataFromResponse, b models.ObjDataFromResponse, key SortKey) int {
+
+	aValue, aOK := FieldValue(a, key.Field)
+	bValue, bOK := FieldValue(b, key.Field)
+
+	return compareValues(aValue, aOK, bValue, bOK, key)
+}
+
+// compareValues compares two values of the field of a sort key, where ok reports whether the value is present
+func compareValues(aValue interface{}, aOK bool, bValue interface{}, bOK bool, key SortKey) int {
+
+	aOK = aOK && aValue != nil
+	bOK = bOK && bValue != nil
+
+	// missing values are placed last in both directions
+	switch {
+	case !aOK && !bOK:
+		return 0
+	case !aOK:
+		return 1
+	case !bOK:
+		return -1
+	}
+
+	cmp := compareSortValues(aValue, bValue)
+	if key.Descending {
+		return -cmp
+	}
+	return cmp
+}
+
+// compareSortValues compares numbers and texts starting with a number numerically and everything else as case-insensitive text
+func compareSortValues(a interface{}, b interface{}) int {
+
+	aNum, aNumOK := numericValue(a)
+	bNum, bNumOK := numericValue(b)
+	if aNumOK && bNumOK {
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
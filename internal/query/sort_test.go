@@ -0,0 +1,180 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/query"
+)
+
+// TestSortObjects tests ParseSort and SortObjects
+func TestSortObjects(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		sort    string
+		wantIDs []string
+	}{
+		{"no sort keeps order", "", []string{"1", "2", "3", "7", "10"}},
+		{"by name", "name", []string{"10", "2", "3", "7", "1"}},
+		{"by name descending", "-name", []string{"1", "7", "3", "2", "10"}},
+		{"missing values last", "data.color", []string{"1", "3", "2", "7", "10"}},
+		{"missing values last descending", "-data.price", []string{"7", "1", "2", "3", "10"}},
+		{"texts starting with numbers", "data.capacity", []string{"10", "1", "2", "3", "7"}},
+		{"multiple keys", "data.color,-name", []string{"1", "3", "7", "2", "10"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := query.ParseSort(tc.sort)
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			objects := testObjects()
+			sorted := query.SortObjects(objects, keys)
+			if got := ids(sorted); !equalIDs(got, tc.wantIDs) {
+				t.Errorf("expected IDs %v, got %v", tc.wantIDs, got)
+			}
+			if got := ids(objects); !equalIDs(got, []string{"1", "2", "3", "7", "10"}) {
+				t.Errorf("expected original list to be unchanged, got %v", got)
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := query.ParseSort("name,price"); err == nil {
+			t.Errorf("expected error for unknown sort field")
+		}
+	})
+}
+
+// TestPaginate tests ParsePageRequest and Paginate
+func TestPaginate(t *testing.T) {
+
+	keys, _ := query.ParseSort("name")
+	objects := query.SortObjects(testObjects(), keys)
+
+	page, err := query.ParsePageRequest("2", "")
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	var gotIDs []string
+	for i := 0; i < 3; i++ {
+		pageObjects, pagination, err := query.Paginate(objects, page, nil, keys)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if pagination.TotalCount != 5 {
+			t.Errorf("expected total count as 5, got %d", pagination.TotalCount)
+		}
+		gotIDs = append(gotIDs, ids(pageObjects)...)
+
+		if pagination.NextPageToken == "" {
+			break
+		}
+		page = query.PageRequest{PageToken: pagination.NextPageToken}
+	}
+
+	if !equalIDs(gotIDs, []string{"10", "2", "3", "7", "1"}) {
+		t.Errorf("expected all objects across pages, got %v", gotIDs)
+	}
+
+	t.Run("objects created and deleted between pages", func(t *testing.T) {
+		_, pagination, err := query.Paginate(objects, query.PageRequest{Limit: 2}, nil, keys)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+
+		// object 2 was the last object of the first page and is deleted, while a new object is created before it
+		changed := []models.ObjDataFromResponse{{ID: "11", Name: "Apple AirPods"}}
+		for _, obj := range objects {
+			if obj.ID != "2" {
+				changed = append(changed, obj)
+			}
+		}
+		changed = query.SortObjects(changed, keys)
+
+		pageObjects, _, err := query.Paginate(changed, query.PageRequest{PageToken: pagination.NextPageToken}, nil, keys)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if got := ids(pageObjects); !equalIDs(got, []string{"3", "7"}) {
+			t.Errorf("expected page to resume after object 2, got %v", got)
+		}
+	})
+
+	t.Run("ties broken by ID", func(t *testing.T) {
+		colorKeys, _ := query.ParseSort("data.color")
+		sorted := query.SortObjects(testObjects(), colorKeys)
+
+		var gotIDs []string
+		page := query.PageRequest{Limit: 1}
+		for i := 0; i < 5; i++ {
+			pageObjects, pagination, err := query.Paginate(sorted, page, nil, colorKeys)
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			gotIDs = append(gotIDs, ids(pageObjects)...)
+			if pagination.NextPageToken == "" {
+				break
+			}
+			page = query.PageRequest{PageToken: pagination.NextPageToken}
+		}
+
+		if !equalIDs(gotIDs, []string{"1", "3", "2", "7", "10"}) {
+			t.Errorf("expected every object once across pages, got %v", gotIDs)
+		}
+	})
+
+	t.Run("pages in the order of the complete list", func(t *testing.T) {
+		var listed []models.ObjDataFromResponse
+		for _, id := range []string{"1", "2", "3", "10", "11", "12", "13"} {
+			listed = append(listed, models.ObjDataFromResponse{ID: id})
+		}
+
+		complete, _, err := query.Paginate(listed, query.PageRequest{}, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+
+		var gotIDs []string
+		page := query.PageRequest{Limit: 3}
+		for i := 0; i < 3; i++ {
+			pageObjects, pagination, err := query.Paginate(listed, page, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			gotIDs = append(gotIDs, ids(pageObjects)...)
+			if pagination.NextPageToken == "" {
+				break
+			}
+			page = query.PageRequest{PageToken: pagination.NextPageToken}
+		}
+
+		wantIDs := []string{"1", "2", "3", "10", "11", "12", "13"}
+		if got := ids(complete); !equalIDs(got, wantIDs) {
+			t.Errorf("expected complete list as %v, got %v", wantIDs, got)
+		}
+		if !equalIDs(gotIDs, wantIDs) {
+			t.Errorf("expected pages as %v, got %v", wantIDs, gotIDs)
+		}
+	})
+
+	t.Run("token reused for different sort", func(t *testing.T) {
+		_, pagination, _ := query.Paginate(objects, query.PageRequest{Limit: 2}, nil, keys)
+		otherKeys, _ := query.ParseSort("-name")
+		_, _, err := query.Paginate(objects, query.PageRequest{PageToken: pagination.NextPageToken}, nil, otherKeys)
+		if err != query.ErrInvalidPageToken {
+			t.Errorf("expected ErrInvalidPageToken, got %v", err)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		for _, limit := range []string{"0", "-1", "101", "ten"} {
+			if _, err := query.ParsePageRequest(limit, ""); err == nil {
+				t.Errorf("expected error for limit %s", limit)
+			}
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// boltOpenTimeout is how long NewBoltStore waits for another process to release the database file
+const boltOpenTimeout = time.Second
+
+// buckets of the database of BoltStore, objects are keyed by their ID and tombstones by the ID of the object they hide
+var (
+	objectsBucket    = []byte("objects")
+	tombstonesBucket = []byte("tombstones")
+)
+
+// boltObject represents strucutre of an object persisted by BoltStore, position keeps the order in which objects were created
+type boltObject struct {
+	Position uint64 `json:"position"`
+	models.NewObj
+}
+
+// BoltStore implements ObjectDataAccessor by persisting objects in an embedded bbolt database file instead of calling external API.
+// IDs and timestamps are generated locally and every change is written in its own transaction, which only touches the changed object.
+// Objects are also held in memory to serve reads. The file is locked while the store is open, so that a single process uses it at a time.
+type BoltStore struct {
+	db    *bolt.DB
+	table *objectTable
+}
+
+// NewBoltStore acts as a constructor method to open the database at path, the file is created when it does not exist.
+// It fails when the file is held open by another process for longer than a second.
+func NewBoltStore(path string) (ObjectDataAccessor, error) {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory of %s, %w", path, err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("error opening store file %s, %w", path, err)
+	}
+
+	objects, tombstones, err := readBoltFile(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error reading store file %s, %w", path, err)
+	}
+
+	s := &BoltStore{
+		db:    db,
+		table: newObjectTable(objects),
+	}
+	for _, id := range tombstones {
+		s.table.tombstones[id] = true
+	}
+	s.table.persist = s.write
+
+	return s, nil
+}
+
+// Close closes the database file, which releases it for other processes
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// GetAllObjects returns all persisted objects
+func (s *BoltStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.table.list(), nil
+}
+
+// GetObjectsByIDs returns the persisted objects with the given IDs
+func (s *BoltStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.table.getMany(IDs)
+}
+
+// GetObjectByID returns a single persisted object
+func (s *BoltStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return models.ObjDataFromResponse{}, err
+	}
+	return s.table.get(ID)
+}
+
+// CreateNewObject persists a new object with a generated ID
+func (s *BoltStore) CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return s.table.create(payload)
+}
+
+// UpdateObject replaces the name and data of a persisted object
+func (s *BoltStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return s.table.update(objID, payload, false)
+}
+
+// UpdateObjectPartially updates the fields of a persisted object that are present in payload
+func (s *BoltStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return s.table.update(objID, payload, true)
+}
+
+// DeleteObject removes a persisted object
+func (s *BoltStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return models.DeleteResult{}, err
+	}
+	return s.table.delete(objID)
+}
+
+// write persists a change of the objects in a single transaction
+func (s *BoltStore) write(change tableChange) error {
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		objects, err := tx.CreateBucketIfNotExists(objectsBucket)
+		if err != nil {
+			return err
+		}
+
+		if change.put != nil {
+			record := boltObject{NewObj: *change.put}
+			if existing := objects.Get([]byte(record.ID)); existing != nil {
+				var previous boltObject
+				if err := json.Unmarshal(existing, &previous); err != nil {
+					return err
+				}
+				record.Position = previous.Position
+			} else if record.Position, err = objects.NextSequence(); err != nil {
+				return err
+			}
+
+			content, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := objects.Put([]byte(record.ID), content); err != nil {
+				return err
+			}
+		}
+
+		if change.deleted != "" {
+			if err := objects.Delete([]byte(change.deleted)); err != nil {
+				return err
+			}
+		}
+
+		if change.tombstone != "" {
+			tombstones, err := tx.CreateBucketIfNotExists(tombstonesBucket)
+			if err != nil {
+				return err
+			}
+			if err := tombstones.Put([]byte(change.tombstone), []byte{}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error writing store file, %w", err)
+	}
+
+	return nil
+}
+
+// readBoltFile reads every persisted object, in the order in which they were created, along with the persisted tombstones
+func readBoltFile(db *bolt.DB) ([]models.NewObj, []string, error) {
+
+	var records []boltObject
+	var tombstones []string
+	err := db.View(func(tx *bolt.Tx) error {
+		if buried := tx.Bucket(tombstonesBucket); buried != nil {
+			err := buried.ForEach(func(key []byte, _ []byte) error {
+				tombstones = append(tombstones, string(key))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		objects := tx.Bucket(objectsBucket)
+		if objects == nil {
+			return nil
+		}
+		return objects.ForEach(func(key []byte, value []byte) error {
+			var record boltObject
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("invalid object %s, %w", key, err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Position < records[j].Position
+	})
+
+	objects := make([]models.NewObj, 0, len(records))
+	for _, record := range records {
+		objects = append(objects, record.NewObj)
+	}
+
+	return objects, tombstones, nil
+}
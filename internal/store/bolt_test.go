@@ -0,0 +1,152 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// reopenBoltStore closes a BoltStore and opens the database at path again, as a restart would
+func reopenBoltStore(t *testing.T, boltStore store.ObjectDataAccessor, path string) store.ObjectDataAccessor {
+
+	if err := boltStore.(io.Closer).Close(); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	reopened, err := store.NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	return reopened
+}
+
+// TestBoltStore tests BoltStore
+func TestBoltStore(t *testing.T) {
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "objects.db")
+
+	boltStore, err := store.NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	t.Cleanup(func() { boltStore.(io.Closer).Close() })
+
+	t.Run("empty store", func(t *testing.T) {
+		objectsList, err := boltStore.GetAllObjects(ctx)
+		if err != nil || len(objectsList) != 0 {
+			t.Errorf("expected no objects, got %v, %v", objectsList, err)
+		}
+		if _, err := boltStore.GetObjectByID(ctx, "1"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	created, err := boltStore.CreateNewObject(ctx, models.ObjDataPayload{
+		Name: "Apple MacBook Pro 16",
+		Data: map[string]interface{}{"year": float64(2019), "CPU model": "Intel Core i9"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if created.ID == "" || created.CreatedAt == "" {
+		t.Fatalf("expected generated ID and createdAt, got %+v", created)
+	}
+
+	second, err := boltStore.CreateNewObject(ctx, models.ObjDataPayload{Name: "Apple iPad Air"})
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	t.Run("persists across instances", func(t *testing.T) {
+		boltStore = reopenBoltStore(t, boltStore, path)
+		objectData, err := boltStore.GetObjectByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if objectData.Name != created.Name || objectData.Data["CPU model"] != "Intel Core i9" {
+			t.Errorf("expected %+v, got %+v", created, objectData)
+		}
+
+		objectsList, _ := boltStore.GetAllObjects(ctx)
+		if len(objectsList) != 2 || objectsList[0].ID != created.ID || objectsList[1].ID != second.ID {
+			t.Errorf("expected objects in order of creation, got %v", objectsList)
+		}
+	})
+
+	t.Run("file locked by open store", func(t *testing.T) {
+		if _, err := store.NewBoltStore(path); err == nil {
+			t.Errorf("expected error opening a file held by another store")
+		}
+	})
+
+	t.Run("returned objects are copies", func(t *testing.T) {
+		objectData, _ := boltStore.GetObjectByID(ctx, created.ID)
+		objectData.Data["CPU model"] = "changed"
+		objectData, _ = boltStore.GetObjectByID(ctx, created.ID)
+		if objectData.Data["CPU model"] != "Intel Core i9" {
+			t.Errorf("expected stored object to be unchanged, got %v", objectData.Data)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated, err := boltStore.UpdateObject(ctx, created.ID, models.ObjDataPayload{Name: "Apple MacBook Pro 14"})
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if updated.UpdatedAt == "" || updated.CreatedAt != created.CreatedAt || updated.Data != nil {
+			t.Errorf("unexpected updated object %+v", updated)
+		}
+
+		updated, err = boltStore.UpdateObjectPartially(ctx, created.ID, models.ObjDataPayload{Data: map[string]interface{}{"color": "silver"}})
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if updated.Name != "Apple MacBook Pro 14" || updated.Data["color"] != "silver" {
+			t.Errorf("unexpected updated object %+v", updated)
+		}
+
+		if _, err := boltStore.UpdateObject(ctx, "unknown", models.ObjDataPayload{Name: "x"}); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		objectsList, err := boltStore.GetObjectsByIDs(ctx, "unknown", created.ID)
+		if err != nil || len(objectsList) != 1 || objectsList[0].ID != created.ID {
+			t.Errorf("expected only %s, got %v, %v", created.ID, objectsList, err)
+		}
+		if _, err := boltStore.GetObjectsByIDs(ctx, "unknown"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		result, err := boltStore.DeleteObject(ctx, created.ID)
+		if err != nil || result.ID != created.ID {
+			t.Fatalf("unexpected result %+v, %v", result, err)
+		}
+		if _, err := boltStore.DeleteObject(ctx, created.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+
+		boltStore = reopenBoltStore(t, boltStore, path)
+		if objectsList, _ := boltStore.GetAllObjects(ctx); len(objectsList) != 1 || objectsList[0].ID != second.ID {
+			t.Errorf("expected deleted object to be persisted, got %v", objectsList)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		invalidPath := filepath.Join(t.TempDir(), "objects.db")
+		os.WriteFile(invalidPath, []byte("{not a database"), 0o644)
+		if _, err := store.NewBoltStore(invalidPath); err == nil {
+			t.Errorf("expected error for invalid store file")
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// states of a circuit breaker
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// ErrCircuitOpen is returned, wrapped in CircuitOpenError, for calls rejected while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned for calls rejected by the circuit breaker along with the time after which calls are let through again
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+// Error implements error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", ErrCircuitOpen, e.RetryAfter)
+}
+
+// Unwrap allows the error to be matched with errors.Is(err, ErrCircuitOpen)
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// BreakerSettings defines when the circuit breaker opens and how it recovers
+type BreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// OpenTimeout is the duration for which calls are rejected once the circuit opens
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of trial calls let through at the same time once OpenTimeout has passed
+	HalfOpenMaxCalls int
+	// IsFailure reports whether an error counts as a failure of the wrapped store, isUpstreamFailure is used when nil
+	IsFailure func(error) bool
+}
+
+// BreakerStore implements ObjectDataAccessor as a circuit breaker in front of another ObjectDataAccessor.
+// While the circuit is open calls fail fast with CircuitOpenError instead of waiting on an unavailable backend.
+type BreakerStore struct {
+	next     ObjectDataAccessor
+	settings BreakerSettings
+
+	mu               sync.Mutex
+	state            string
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+}
+
+// NewBreakerStore acts as a constructor method to wrap a store with a circuit breaker
+func NewBreakerStore(next ObjectDataAccessor, settings BreakerSettings) ObjectDataAccessor {
+
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.HalfOpenMaxCalls < 1 {
+		settings.HalfOpenMaxCalls = 1
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = isUpstreamFailure
+	}
+
+	return &BreakerStore{
+		next:     next,
+		settings: settings,
+		state:    StateClosed,
+	}
+}
+
+// State returns the current state of the circuit
+func (b *BreakerStore) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.settings.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// GetAllObjects fetches all objects from the wrapped store unless the circuit is open
+func (b *BreakerStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	objectsList, err := b.next.GetAllObjects(ctx)
+	b.record(err)
+	return objectsList, err
+}
+
+// GetObjectsByIDs fetches objects based on IDs from the wrapped store unless the circuit is open
+func (b *BreakerStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	objectsList, err := b.next.GetObjectsByIDs(ctx, IDs...)
+	b.record(err)
+	return objectsList, err
+}
+
+// GetObjectByID fetches a single object from the wrapped store unless the circuit is open
+func (b *BreakerStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+	if err := b.allow(); err != nil {
+		return models.ObjDataFromResponse{}, err
+	}
+	objectData, err := b.next.GetObjectByID(ctx, ID)
+	b.record(err)
+	return objectData, err
+}
+
+// CreateNewObject creates an object in the wrapped store unless the circuit is open
+func (b *BreakerStore) CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := b.allow(); err != nil {
+		return models.NewObj{}, err
+	}
+	objectData, err := b.next.CreateNewObject(ctx, payload)
+	b.record(err)
+	return objectData, err
+}
+
+// UpdateObject updates an object in the wrapped store unless the circuit is open
+func (b *BreakerStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := b.allow(); err != nil {
+		return models.NewObj{}, err
+	}
+	objectData, err := b.next.UpdateObject(ctx, objID, payload)
+	b.record(err)
+	return objectData, err
+}
+
+// UpdateObjectPartially partially updates an object in the wrapped store unless the circuit is open
+func (b *BreakerStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := b.allow(); err != nil {
+		return models.NewObj{}, err
+	}
+	objectData, err := b.next.UpdateObjectPartially(ctx, objID, payload)
+	b.record(err)
+	return objectData, err
+}
+
+// DeleteObject deletes an object in the wrapped store unless the circuit is open
+func (b *BreakerStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
+	if err := b.allow(); err != nil {
+		return models.DeleteResult{}, err
+	}
+	result, err := b.next.DeleteObject(ctx, objID)
+	b.record(err)
+	return result, err
+}
+
+// allow decides whether a call is let through, moving an open circuit to half-open once its timeout has passed
+func (b *BreakerStore) allow() error {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		elapsed := time.Since(b.openedAt)
+		if elapsed < b.settings.OpenTimeout {
+			return &CircuitOpenError{RetryAfter: b.settings.OpenTimeout - elapsed}
+		}
+		b.state = StateHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	if b.state == StateHalfOpen {
+		if b.halfOpenInFlight >= b.settings.HalfOpenMaxCalls {
+			return &CircuitOpenError{RetryAfter: b.settings.OpenTimeout}
+		}
+		b.halfOpenInFlight++
+	}
+
+	return nil
+}
+
+// record updates the state of the circuit with the outcome of a call that was let through
+func (b *BreakerStore) record(err error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+
+	// a call cancelled by the caller tells nothing about the wrapped store, so it neither counts as a failure nor as a success
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := err != nil && b.settings.IsFailure(err)
+
+	if b.state == StateHalfOpen {
+		if failed {
+			b.open()
+			return
+		}
+		b.state = StateClosed
+		b.failures = 0
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == StateClosed && b.failures >= b.settings.FailureThreshold {
+		b.open()
+	}
+}
+
+func (b *BreakerStore) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.halfOpenInFlight = 0
+}
+
+// isUpstreamFailure reports whether an error means that the external API is failing, as opposed to
+// the caller giving up or the external API rejecting the request itself
+func isUpstreamFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, ErrUpstreamUnavailable) || errors.Is(err, context.DeadlineExceeded)
+}
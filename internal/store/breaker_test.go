@@ -0,0 +1,130 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// flakyStore embeds ObjectDataAccessor and fails its reads with err while it is set
+type flakyStore struct {
+	store.ObjectDataAccessor
+	err   error
+	calls int
+}
+
+// GetObjectByID returns a mock object or the configured error
+func (f *flakyStore) GetObjectByID(_ context.Context, ID string) (models.ObjDataFromResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return models.ObjDataFromResponse{}, f.err
+	}
+	return models.ObjDataFromResponse{ID: ID, Name: "Object " + ID}, nil
+}
+
+// TestBreakerStore tests BreakerStore
+func TestBreakerStore(t *testing.T) {
+
+	settings := store.BreakerSettings{FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond}
+
+	t.Run("opens after consecutive failures and recovers", func(t *testing.T) {
+		next := &flakyStore{err: &store.UpstreamStatusError{StatusCode: 503}}
+		breaker := store.NewBreakerStore(next, settings)
+
+		breaker.GetObjectByID(context.Background(), "1")
+		breaker.GetObjectByID(context.Background(), "1")
+
+		_, err := breaker.GetObjectByID(context.Background(), "1")
+		var openErr *store.CircuitOpenError
+		if !errors.As(err, &openErr) || !errors.Is(err, store.ErrCircuitOpen) {
+			t.Fatalf("expected CircuitOpenError, got %v", err)
+		}
+		if openErr.RetryAfter <= 0 || openErr.RetryAfter > settings.OpenTimeout {
+			t.Errorf("expected retry after within open timeout, got %v", openErr.RetryAfter)
+		}
+		if next.calls != 2 {
+			t.Errorf("expected 2 calls to reach wrapped store, got %d", next.calls)
+		}
+
+		time.Sleep(settings.OpenTimeout)
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateHalfOpen {
+			t.Errorf("expected state %s, got %s", store.StateHalfOpen, state)
+		}
+
+		next.err = nil
+		if _, err := breaker.GetObjectByID(context.Background(), "1"); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateClosed {
+			t.Errorf("expected state %s, got %s", store.StateClosed, state)
+		}
+	})
+
+	t.Run("failed trial call opens again", func(t *testing.T) {
+		next := &flakyStore{err: &store.UpstreamStatusError{StatusCode: 503}}
+		breaker := store.NewBreakerStore(next, settings)
+
+		breaker.GetObjectByID(context.Background(), "1")
+		breaker.GetObjectByID(context.Background(), "1")
+		time.Sleep(settings.OpenTimeout)
+
+		breaker.GetObjectByID(context.Background(), "1")
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateOpen {
+			t.Errorf("expected state %s, got %s", store.StateOpen, state)
+		}
+	})
+
+	t.Run("client errors do not open", func(t *testing.T) {
+		next := &flakyStore{err: store.ErrNotFound}
+		breaker := store.NewBreakerStore(next, settings)
+
+		for i := 0; i < 3; i++ {
+			breaker.GetObjectByID(context.Background(), "99")
+		}
+
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateClosed {
+			t.Errorf("expected state %s, got %s", store.StateClosed, state)
+		}
+	})
+
+	t.Run("cancelled calls do not open", func(t *testing.T) {
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		})
+		breaker := store.NewBreakerStore(store.NewStore("https://api.example.test", store.WithTransport(transport)), settings)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for i := 0; i < 3; i++ {
+			_, err := breaker.GetObjectByID(ctx, "1")
+			if !errors.Is(err, context.Canceled) || errors.Is(err, store.ErrUpstreamUnavailable) {
+				t.Errorf("expected cancelled error that is not ErrUpstreamUnavailable, got %v", err)
+			}
+		}
+
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateClosed {
+			t.Errorf("expected state %s, got %s", store.StateClosed, state)
+		}
+	})
+
+	t.Run("cancelled trial call does not close", func(t *testing.T) {
+		next := &flakyStore{err: &store.UpstreamStatusError{StatusCode: 503}}
+		breaker := store.NewBreakerStore(next, settings)
+
+		breaker.GetObjectByID(context.Background(), "1")
+		breaker.GetObjectByID(context.Background(), "1")
+		time.Sleep(settings.OpenTimeout)
+
+		next.err = context.Canceled
+		breaker.GetObjectByID(context.Background(), "1")
+		if state := breaker.(*store.BreakerStore).State(); state != store.StateHalfOpen {
+			t.Errorf("expected state %s, got %s", store.StateHalfOpen, state)
+		}
+	})
+}
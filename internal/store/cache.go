@@ -0,0 +1,267 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// values recorded by CacheStatus
+const (
+	CacheHit  = "HIT"
+	CacheMiss = "MISS"
+)
+
+// prefixes of cache keys, a key is the prefix followed by the requested ID(s)
+const (
+	allObjectsKey    = "all"
+	objectKeyPrefix  = "id:"
+	objectsKeyPrefix = "ids:"
+)
+
+type cacheStatusKey struct{}
+
+// CacheStatus records whether the reads performed for a request were served from cache
+type CacheStatus struct {
+	mu    sync.Mutex
+	value string
+}
+
+// WithCacheStatus returns a copy of ctx that carries a CacheStatus for the reads performed with it
+func WithCacheStatus(ctx context.Context) (context.Context, *CacheStatus) {
+	status := &CacheStatus{}
+	return context.WithValue(ctx, cacheStatusKey{}, status), status
+}
+
+// String returns CacheHit when every read was served from cache, CacheMiss when at least one was not,
+// and an empty string when no cached read was performed
+func (c *CacheStatus) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+func (c *CacheStatus) record(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !hit {
+		c.value = CacheMiss
+	} else if c.value != CacheMiss {
+		c.value = CacheHit
+	}
+}
+
+func recordCacheStatus(ctx context.Context, hit bool) {
+	if status, ok := ctx.Value(cacheStatusKey{}).(*CacheStatus); ok {
+		status.record(hit)
+	}
+}
+
+// cacheEntry represents a value held in cache along with its expiry
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// CachedStore implements ObjectDataAccessor as a read-through cache in front of another ObjectDataAccessor.
+// Reads are cached for a fixed duration and the least recently used entries are evicted once the cache is full.
+// Writes are always sent to the wrapped store and invalidate the entries they may have changed.
+// Cached values are deep copies, so that callers changing the data of an object do not change the cache.
+type CachedStore struct {
+	next       ObjectDataAccessor
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	// generation is incremented by every invalidation, a read only fills the cache when no write completed while it was fetching,
+	// as the value it fetched may have been read before the write
+	generation uint64
+}
+
+// NewCachedStore acts as a constructor method to wrap a store with a cache of at most maxEntries entries, each valid for ttl
+func NewCachedStore(next ObjectDataAccessor, ttl time.Duration, maxEntries int) ObjectDataAccessor {
+	return &CachedStore{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// GetAllObjects serves all objects from cache, fetching them from the wrapped store when not cached
+func (c *CachedStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
+
+	value, generation, ok := c.get(allObjectsKey)
+	if ok {
+		recordCacheStatus(ctx, true)
+		return copyObjects(value.([]models.ObjDataFromResponse)), nil
+	}
+	recordCacheStatus(ctx, false)
+
+	objectsList, err := c.next.GetAllObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(allObjectsKey, copyObjects(objectsList), generation)
+	return objectsList, nil
+}
+
+// GetObjectsByIDs serves objects based on IDs from cache, fetching them from the wrapped store when not cached
+func (c *CachedStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
+
+	key := objectsKeyPrefix + strings.Join(IDs, "\x00")
+	value, generation, ok := c.get(key)
+	if ok {
+		recordCacheStatus(ctx, true)
+		return copyObjects(value.([]models.ObjDataFromResponse)), nil
+	}
+	recordCacheStatus(ctx, false)
+
+	objectsList, err := c.next.GetObjectsByIDs(ctx, IDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, copyObjects(objectsList), generation)
+	return objectsList, nil
+}
+
+// GetObjectByID serves a single object from cache, fetching it from the wrapped store when not cached
+func (c *CachedStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+
+	key := objectKeyPrefix + ID
+	value, generation, ok := c.get(key)
+	if ok {
+		recordCacheStatus(ctx, true)
+		return copyObjData(value.(models.ObjDataFromResponse)), nil
+	}
+	recordCacheStatus(ctx, false)
+
+	objectData, err := c.next.GetObjectByID(ctx, ID)
+	if err != nil {
+		return objectData, err
+	}
+
+	c.set(key, copyObjData(objectData), generation)
+	return objectData, nil
+}
+
+// CreateNewObject creates the object in the wrapped store and invalidates cached lists
+func (c *CachedStore) CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error) {
+	defer c.invalidate("")
+	return c.next.CreateNewObject(ctx, payload)
+}
+
+// UpdateObject updates the object in the wrapped store and invalidates the cached object and lists
+func (c *CachedStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	defer c.invalidate(objID)
+	return c.next.UpdateObject(ctx, objID, payload)
+}
+
+// UpdateObjectPartially partially updates the object in the wrapped store and invalidates the cached object and lists
+func (c *CachedStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	defer c.invalidate(objID)
+	return c.next.UpdateObjectPartially(ctx, objID, payload)
+}
+
+// DeleteObject deletes the object in the wrapped store and invalidates the cached object and lists
+func (c *CachedStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
+	defer c.invalidate(objID)
+	return c.next.DeleteObject(ctx, objID)
+}
+
+// get returns the value of an unexpired entry and marks it as recently used.
+// It also returns the current generation, which a miss passes to set along with the fetched value.
+func (c *CachedStore) get(key string) (interface{}, uint64, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, c.generation, false
+	}
+
+	c.lru.MoveToFront(element)
+	return entry.value, c.generation, true
+}
+
+// set adds or replaces an entry, evicting the least recently used entries when the cache is full.
+// The value is dropped when the cache has been invalidated since generation.
+func (c *CachedStore) set(key string, value interface{}, generation uint64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// invalidate removes every cached list and, when objID is provided, the cached object with that ID
+func (c *CachedStore) invalidate(objID string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, element := range c.entries {
+		if key == allObjectsKey || strings.HasPrefix(key, objectsKeyPrefix) || (objID != "" && key == objectKeyPrefix+objID) {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *CachedStore) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// copyObjects deep copies a list of objects, so that a cached list is neither reordered nor changed by its callers
+func copyObjects(objects []models.ObjDataFromResponse) []models.ObjDataFromResponse {
+	copied := make([]models.ObjDataFromResponse, len(objects))
+	for i, obj := range objects {
+		copied[i] = copyObjData(obj)
+	}
+	return copied
+}
+
+// copyObjData copies an object along with its data
+func copyObjData(obj models.ObjDataFromResponse) models.ObjDataFromResponse {
+	obj.Data = copyData(obj.Data)
+	return obj
+}
@@ -0,0 +1,177 @@
+// Package store_test tests all the functionality present in store package
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// countingStore embeds ObjectDataAccessor and counts the reads that reach it
+type countingStore struct {
+	store.ObjectDataAccessor
+	reads int
+}
+
+// GetAllObjects returns a mock list of objects
+func (c *countingStore) GetAllObjects(_ context.Context) ([]models.ObjDataFromResponse, error) {
+	c.reads++
+	return []models.ObjDataFromResponse{{ID: "1", Name: "One"}, {ID: "2", Name: "Two"}}, nil
+}
+
+// GetObjectByID returns a mock object based on requested ID
+func (c *countingStore) GetObjectByID(_ context.Context, ID string) (models.ObjDataFromResponse, error) {
+	c.reads++
+	return models.ObjDataFromResponse{ID: ID, Name: "Object " + ID, Data: map[string]interface{}{"color": "Black"}}, nil
+}
+
+// slowStore embeds countingStore and holds reads of an object until released, as a slow call to external API would
+type slowStore struct {
+	*countingStore
+	started chan struct{}
+	release chan struct{}
+}
+
+// GetObjectByID returns a mock object once the read is released
+func (s slowStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+	obj, err := s.countingStore.GetObjectByID(ctx, ID)
+	s.started <- struct{}{}
+	<-s.release
+	return obj, err
+}
+
+// UpdateObject returns a mock updated object
+func (c *countingStore) UpdateObject(_ context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	return models.NewObj{ID: objID, Name: payload.Name}, nil
+}
+
+// TestCachedStore tests CachedStore
+func TestCachedStore(t *testing.T) {
+
+	t.Run("hit after miss", func(t *testing.T) {
+		next := &countingStore{}
+		cached := store.NewCachedStore(next, time.Minute, 10)
+
+		ctx, status := store.WithCacheStatus(context.Background())
+		if _, err := cached.GetAllObjects(ctx); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if status.String() != store.CacheMiss {
+			t.Errorf("expected cache status %s, got %s", store.CacheMiss, status.String())
+		}
+
+		ctx, status = store.WithCacheStatus(context.Background())
+		objects, _ := cached.GetAllObjects(ctx)
+		if status.String() != store.CacheHit {
+			t.Errorf("expected cache status %s, got %s", store.CacheHit, status.String())
+		}
+		if next.reads != 1 {
+			t.Errorf("expected 1 read from wrapped store, got %d", next.reads)
+		}
+
+		// reordering a returned list must not change the cached list
+		objects[0], objects[1] = objects[1], objects[0]
+		objects, _ = cached.GetAllObjects(context.Background())
+		if objects[0].ID != "1" {
+			t.Errorf("expected cached list to keep its order, got %v", objects)
+		}
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		next := &countingStore{}
+		cached := store.NewCachedStore(next, time.Millisecond, 10)
+
+		cached.GetObjectByID(context.Background(), "1")
+		time.Sleep(5 * time.Millisecond)
+		cached.GetObjectByID(context.Background(), "1")
+
+		if next.reads != 2 {
+			t.Errorf("expected 2 reads from wrapped store, got %d", next.reads)
+		}
+	})
+
+	t.Run("least recently used entry evicted", func(t *testing.T) {
+		next := &countingStore{}
+		cached := store.NewCachedStore(next, time.Minute, 2)
+
+		cached.GetObjectByID(context.Background(), "1")
+		cached.GetObjectByID(context.Background(), "2")
+		cached.GetObjectByID(context.Background(), "1")
+		cached.GetObjectByID(context.Background(), "3")
+
+		// 2 was the least recently used entry when 3 was added
+		cached.GetObjectByID(context.Background(), "1")
+		cached.GetObjectByID(context.Background(), "2")
+
+		if next.reads != 4 {
+			t.Errorf("expected 4 reads from wrapped store, got %d", next.reads)
+		}
+	})
+
+	t.Run("invalidated on update", func(t *testing.T) {
+		next := &countingStore{}
+		cached := store.NewCachedStore(next, time.Minute, 10)
+
+		cached.GetAllObjects(context.Background())
+		cached.GetObjectByID(context.Background(), "1")
+		cached.GetObjectByID(context.Background(), "2")
+
+		if _, err := cached.UpdateObject(context.Background(), "1", models.ObjDataPayload{Name: "Updated"}); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+
+		cached.GetAllObjects(context.Background())
+		cached.GetObjectByID(context.Background(), "1")
+		cached.GetObjectByID(context.Background(), "2")
+
+		if next.reads != 5 {
+			t.Errorf("expected 5 reads from wrapped store, got %d", next.reads)
+		}
+	})
+
+	t.Run("read started before update not cached", func(t *testing.T) {
+		next := slowStore{countingStore: &countingStore{}, started: make(chan struct{}, 2), release: make(chan struct{})}
+		cached := store.NewCachedStore(next, time.Minute, 10)
+
+		done := make(chan struct{})
+		go func() {
+			cached.GetObjectByID(context.Background(), "1")
+			close(done)
+		}()
+
+		// the read fetched the object before the update and completes after it
+		<-next.started
+		if _, err := cached.UpdateObject(context.Background(), "1", models.ObjDataPayload{Name: "Updated"}); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		close(next.release)
+		<-done
+
+		cached.GetObjectByID(context.Background(), "1")
+		if next.reads != 2 {
+			t.Errorf("expected 2 reads from wrapped store, got %d", next.reads)
+		}
+	})
+
+	t.Run("returned data not shared with cache", func(t *testing.T) {
+		next := &countingStore{}
+		cached := store.NewCachedStore(next, time.Minute, 10)
+
+		objData, _ := cached.GetObjectByID(context.Background(), "1")
+		objData.Data["color"] = "Changed"
+
+		objData, _ = cached.GetObjectByID(context.Background(), "1")
+		if objData.Data["color"] != "Black" {
+			t.Errorf("expected cached data to be unchanged, got %v", objData.Data)
+		}
+		objData.Data["color"] = "Changed"
+
+		objData, _ = cached.GetObjectByID(context.Background(), "1")
+		if objData.Data["color"] != "Black" {
+			t.Errorf("expected cached data to be unchanged, got %v", objData.Data)
+		}
+	})
+}
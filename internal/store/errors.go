@@ -0,0 +1,60 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an unexpected response body is kept in UpstreamStatusError
+const maxErrorBodySize = 1024
+
+// errors returned by the store, to be matched with errors.Is
+var (
+	// ErrNotFound is returned when the requested object does not exist
+	ErrNotFound = errors.New("object not found")
+	// ErrConflict is returned when the object exists but cannot be modified, as for the reserved objects of external API
+	ErrConflict = errors.New("object cannot be modified")
+	// ErrUpstreamUnavailable is returned when the external API cannot be reached or fails to serve the request
+	ErrUpstreamUnavailable = errors.New("external API is unavailable")
+)
+
+// UpstreamStatusError is returned when the external API responds with an unexpected status.
+// It matches ErrNotFound, ErrConflict or ErrUpstreamUnavailable based on the status code.
+type UpstreamStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements error interface
+func (e *UpstreamStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d, %s", e.StatusCode, e.Body)
+}
+
+// Is allows the error to be matched with the sentinel error of its status code using errors.Is
+func (e *UpstreamStatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		// external API answers 405 for attempts to modify reserved objects
+		return e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusConflict
+	case ErrUpstreamUnavailable:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newUpstreamStatusError builds an UpstreamStatusError from an unexpected response of external API
+func newUpstreamStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return &UpstreamStatusError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
@@ -15,5 +15,5 @@ type ObjectDataAccessor interface {
 	CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error)
 	UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error)
 	UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error)
-	DeleteObject(ctx context.Context, objID string) (map[string]string, error)
+	DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error)
 }
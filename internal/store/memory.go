@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// reservedObjectsJSON holds the reserved objects of external API, which cannot be modified there
+//
+//go:embed fixtures/reserved_objects.json
+var reservedObjectsJSON []byte
+
+// ReservedObjects returns a copy of the reserved objects of external API
+func ReservedObjects() []models.NewObj {
+
+	var objects []models.NewObj
+	if err := json.Unmarshal(reservedObjectsJSON, &objects); err != nil {
+		panic(fmt.Sprintf("invalid reserved objects fixture, %v", err))
+	}
+
+	return objects
+}
+
+// MemoryStore implements ObjectDataAccessor by holding objects in memory, it is safe for concurrent use.
+// It serves as a backend for development and as a test double for ObjectDataAccessor, which is exported to other modules by package memstore.
+type MemoryStore struct {
+	table *objectTable
+}
+
+// NewMemoryStore acts as a constructor method to create a store holding the given objects, objects created later get a generated ID
+func NewMemoryStore(objects []models.NewObj) ObjectDataAccessor {
+	return &MemoryStore{
+		table: newObjectTable(objects),
+	}
+}
+
+// NewMemoryStoreFromFile acts as a constructor method to create a store holding the objects read from a JSON file
+func NewMemoryStoreFromFile(path string) (ObjectDataAccessor, error) {
+
+	objects, err := readSeedFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMemoryStore(objects), nil
+}
+
+// readSeedFile reads the objects listed in a JSON seed file, an empty file lists no objects
+func readSeedFile(path string) ([]models.NewObj, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seed file, %w", err)
+	}
+
+	var objects []models.NewObj
+	if len(content) == 0 {
+		return objects, nil
+	}
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return nil, fmt.Errorf("error decoding seed file %s, %w", path, err)
+	}
+
+	return objects, nil
+}
+
+// GetAllObjects returns all objects
+func (m *MemoryStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.table.list(), nil
+}
+
+// GetObjectsByIDs returns the objects with the given IDs
+func (m *MemoryStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.table.getMany(IDs)
+}
+
+// GetObjectByID returns a single object
+func (m *MemoryStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return models.ObjDataFromResponse{}, err
+	}
+	return m.table.get(ID)
+}
+
+// CreateNewObject adds a new object with a generated ID
+func (m *MemoryStore) CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return m.table.create(payload)
+}
+
+// UpdateObject replaces the name and data of an object
+func (m *MemoryStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return m.table.update(objID, payload, false)
+}
+
+// UpdateObjectPartially updates the fields of an object that are present in payload
+func (m *MemoryStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return m.table.update(objID, payload, true)
+}
+
+// DeleteObject removes an object
+func (m *MemoryStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return models.DeleteResult{}, err
+	}
+	return m.table.delete(objID)
+}
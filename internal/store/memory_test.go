@@ -0,0 +1,74 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// TestMemoryStore tests MemoryStore
+func TestMemoryStore(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("seeded with reserved objects", func(t *testing.T) {
+		memoryStore := store.NewMemoryStore(store.ReservedObjects())
+
+		objectsList, err := memoryStore.GetAllObjects(ctx)
+		if err != nil || len(objectsList) != 13 {
+			t.Fatalf("expected 13 objects, got %d, %v", len(objectsList), err)
+		}
+		if objectsList[0].ID != "1" || objectsList[12].ID != "13" {
+			t.Errorf("expected objects in fixture order, got %s to %s", objectsList[0].ID, objectsList[12].ID)
+		}
+
+		objectData, err := memoryStore.GetObjectByID(ctx, "7")
+		if err != nil || objectData.Name != "Apple MacBook Pro 16" {
+			t.Errorf("unexpected object %+v, %v", objectData, err)
+		}
+	})
+
+	t.Run("stores are independent", func(t *testing.T) {
+		first := store.NewMemoryStore(store.ReservedObjects())
+		second := store.NewMemoryStore(store.ReservedObjects())
+
+		if _, err := first.DeleteObject(ctx, "1"); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if _, err := first.GetObjectByID(ctx, "1"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if _, err := second.GetObjectByID(ctx, "1"); err != nil {
+			t.Errorf("expected object to remain in other store, got %v", err)
+		}
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		memoryStore := store.NewMemoryStore(nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				obj, err := memoryStore.CreateNewObject(ctx, models.ObjDataPayload{Name: "Object"})
+				if err != nil {
+					t.Errorf("unexpected error occured %v", err)
+					return
+				}
+				memoryStore.UpdateObjectPartially(ctx, obj.ID, models.ObjDataPayload{Data: map[string]interface{}{"color": "Red"}})
+				memoryStore.GetAllObjects(ctx)
+			}()
+		}
+		wg.Wait()
+
+		objectsList, _ := memoryStore.GetAllObjects(ctx)
+		if len(objectsList) != 50 {
+			t.Errorf("expected 50 objects, got %d", len(objectsList))
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures the HTTP client and the requests that ObjectStore uses to call the external API
+type Option func(*clientOptions)
+
+// clientOptions holds the settings used to build the HTTP client and the requests of ObjectStore
+type clientOptions struct {
+	timeout             time.Duration
+	maxIdleConnsPerHost int
+	tlsConfig           *tls.Config
+	proxy               func(*http.Request) (*url.URL, error)
+	transport           http.RoundTripper
+	retry               RetryPolicy
+	apiKey              string
+	collection          string
+}
+
+// WithTimeout limits the time taken by a single call to the external API, including reading the response body.
+// Calls are otherwise only limited by the deadline of their context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept open to the external API for reuse
+func WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) Option {
+	return func(o *clientOptions) {
+		o.maxIdleConnsPerHost = maxIdleConnsPerHost
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the external API
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithProxy sets the function that selects the proxy for a call to the external API.
+// The proxy is read from HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables by default.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(o *clientOptions) {
+		o.proxy = proxy
+	}
+}
+
+// WithTransport replaces the default transport with a custom RoundTripper, for example to stub the external API in tests.
+// The idle connection, TLS and proxy options are not applied to a custom transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithAPIKey sets the API key sent in the x-api-key header of every call to the external API
+func WithAPIKey(apiKey string) Option {
+	return func(o *clientOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithCollection stores objects in a private collection of the external API instead of the public objects,
+// which requires an API key to be set with WithAPIKey
+func WithCollection(collection string) Option {
+	return func(o *clientOptions) {
+		o.collection = collection
+	}
+}
+
+// newClientOptions applies opts over the default settings
+func newClientOptions(opts ...Option) clientOptions {
+
+	options := clientOptions{
+		maxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+		proxy:               http.ProxyFromEnvironment,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// newHTTPClient builds the HTTP client shared by all the calls of ObjectStore
+func newHTTPClient(options clientOptions) *http.Client {
+
+	transport := options.transport
+	if transport == nil {
+		transport = &http.Transport{
+			Proxy:               options.proxy,
+			TLSClientConfig:     options.tlsConfig,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: options.maxIdleConnsPerHost,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   options.timeout,
+		Transport: transport,
+	}
+}
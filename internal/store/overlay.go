@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// localStore is implemented by the stores that hold objects locally, which is required for an object to be
+// stored under the ID it has in external API
+type localStore interface {
+	ObjectDataAccessor
+	localTable() *objectTable
+}
+
+// localTable returns the objects held by the store
+func (m *MemoryStore) localTable() *objectTable {
+	return m.table
+}
+
+// localTable returns the objects held by the store
+func (s *BoltStore) localTable() *objectTable {
+	return s.table
+}
+
+// OverlayStore implements ObjectDataAccessor by reading objects from a read-only store, such as external API,
+// and writing every change to a local store. Local objects take the place of the objects with the same ID
+// in external API and deleted objects are hidden with tombstones, which are kept by the local store along with its objects.
+type OverlayStore struct {
+	upstream   ObjectDataAccessor
+	local      *objectTable
+	localStore ObjectDataAccessor
+}
+
+// NewOverlayStore acts as a constructor method to overlay the changes held by local on the objects of upstream.
+// local must have been created with NewMemoryStore, NewMemoryStoreFromFile or NewBoltStore.
+func NewOverlayStore(upstream ObjectDataAccessor, local ObjectDataAccessor) (ObjectDataAccessor, error) {
+
+	localObjects, ok := local.(localStore)
+	if !ok {
+		return nil, fmt.Errorf("overlay requires a local store, got %T", local)
+	}
+
+	return &OverlayStore{
+		upstream:   upstream,
+		local:      localObjects.localTable(),
+		localStore: local,
+	}, nil
+}
+
+// Close closes the local store when it holds an open file
+func (o *OverlayStore) Close() error {
+	return closeStore(o.localStore)
+}
+
+// GetAllObjects returns the objects of upstream with local changes applied, followed by the objects created locally
+func (o *OverlayStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
+
+	upstreamObjects, err := o.upstream.GetAllObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	localObjects := o.local.list()
+	localByID := make(map[string]models.ObjDataFromResponse, len(localObjects))
+	for _, obj := range localObjects {
+		localByID[obj.ID] = obj
+	}
+
+	objectsList := make([]models.ObjDataFromResponse, 0, len(upstreamObjects)+len(localObjects))
+	for _, obj := range upstreamObjects {
+		if o.deleted(obj.ID) {
+			continue
+		}
+		if localObj, ok := localByID[obj.ID]; ok {
+			obj = localObj
+			delete(localByID, obj.ID)
+		}
+		objectsList = append(objectsList, obj)
+	}
+
+	for _, obj := range localObjects {
+		if _, ok := localByID[obj.ID]; ok {
+			objectsList = append(objectsList, obj)
+		}
+	}
+
+	return objectsList, nil
+}
+
+// GetObjectsByIDs returns the objects with the given IDs, only fetching the objects without local changes from upstream
+func (o *OverlayStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
+
+	found := make(map[string]models.ObjDataFromResponse, len(IDs))
+	var upstreamIDs []string
+	for _, id := range IDs {
+		if o.deleted(id) {
+			continue
+		}
+		if obj, err := o.local.get(id); err == nil {
+			found[id] = obj
+			continue
+		}
+		upstreamIDs = append(upstreamIDs, id)
+	}
+
+	if len(upstreamIDs) > 0 {
+		upstreamObjects, err := o.upstream.GetObjectsByIDs(ctx, upstreamIDs...)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		for _, obj := range upstreamObjects {
+			found[obj.ID] = obj
+		}
+	}
+
+	var objectsList []models.ObjDataFromResponse
+	for _, id := range IDs {
+		if obj, ok := found[id]; ok {
+			objectsList = append(objectsList, obj)
+			delete(found, id)
+		}
+	}
+
+	if len(objectsList) == 0 {
+		return nil, fmt.Errorf("%w, no object with requested ids", ErrNotFound)
+	}
+
+	return objectsList, nil
+}
+
+// GetObjectByID returns the local object with the given ID, fetching it from upstream when it has no local changes
+func (o *OverlayStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
+
+	if o.deleted(ID) {
+		return models.ObjDataFromResponse{}, fmt.Errorf("%w, object with id %s has been deleted", ErrNotFound, ID)
+	}
+
+	if obj, err := o.local.get(ID); err == nil {
+		return obj, nil
+	}
+
+	return o.upstream.GetObjectByID(ctx, ID)
+}
+
+// CreateNewObject creates the object locally
+func (o *OverlayStore) CreateNewObject(ctx context.Context, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := ctx.Err(); err != nil {
+		return models.NewObj{}, err
+	}
+	return o.local.create(payload)
+}
+
+// UpdateObject replaces the name and data of an object locally
+func (o *OverlayStore) UpdateObject(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := o.copyToLocal(ctx, objID); err != nil {
+		return models.NewObj{}, err
+	}
+	return o.local.update(objID, payload, false)
+}
+
+// UpdateObjectPartially updates the fields of an object that are present in payload locally
+func (o *OverlayStore) UpdateObjectPartially(ctx context.Context, objID string, payload models.ObjDataPayload) (models.NewObj, error) {
+	if err := o.copyToLocal(ctx, objID); err != nil {
+		return models.NewObj{}, err
+	}
+	return o.local.update(objID, payload, true)
+}
+
+// DeleteObject removes the local object and hides the object with the same ID in upstream
+func (o *OverlayStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
+
+	if o.deleted(objID) {
+		return models.DeleteResult{}, fmt.Errorf("%w, object with id %s has been deleted", ErrNotFound, objID)
+	}
+
+	// an object without local changes must exist in upstream to be deleted
+	if _, err := o.local.get(objID); errors.Is(err, ErrNotFound) {
+		if _, err := o.upstream.GetObjectByID(ctx, objID); err != nil {
+			return models.DeleteResult{}, err
+		}
+	}
+
+	if err := o.local.bury(objID); err != nil {
+		return models.DeleteResult{}, err
+	}
+
+	return models.DeleteResult{
+		ID:      objID,
+		Message: fmt.Sprintf("Object with id = %s has been deleted.", objID),
+	}, nil
+}
+
+// copyToLocal stores the object of upstream with the given ID locally, unless it already has local changes
+func (o *OverlayStore) copyToLocal(ctx context.Context, objID string) error {
+
+	if o.deleted(objID) {
+		return fmt.Errorf("%w, object with id %s has been deleted", ErrNotFound, objID)
+	}
+
+	if _, err := o.local.get(objID); err == nil {
+		return nil
+	}
+
+	obj, err := o.upstream.GetObjectByID(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	return o.local.put(models.NewObj{
+		ID:   obj.ID,
+		Name: obj.Name,
+		Data: obj.Data,
+	})
+}
+
+func (o *OverlayStore) deleted(objID string) bool {
+	return o.local.buried(objID)
+}
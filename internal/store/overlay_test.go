@@ -0,0 +1,155 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// TestOverlayStore tests OverlayStore
+func TestOverlayStore(t *testing.T) {
+
+	ctx := context.Background()
+	upstream := store.NewMemoryStore(store.ReservedObjects())
+
+	overlay, err := store.NewOverlayStore(upstream, store.NewMemoryStore(nil))
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	updated, err := overlay.UpdateObjectPartially(ctx, "7", models.ObjDataPayload{Name: "Apple MacBook Pro 16 (2023)"})
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if updated.ID != "7" || updated.Data["CPU model"] != "Intel Core i9" {
+		t.Errorf("expected update to keep ID and data of upstream object, got %+v", updated)
+	}
+
+	created, err := overlay.CreateNewObject(ctx, models.ObjDataPayload{Name: "Apple AirPods Pro"})
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if _, err := overlay.DeleteObject(ctx, "1"); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	t.Run("upstream is unchanged", func(t *testing.T) {
+		objectData, _ := upstream.GetObjectByID(ctx, "7")
+		if objectData.Name != "Apple MacBook Pro 16" {
+			t.Errorf("expected upstream object to be unchanged, got %s", objectData.Name)
+		}
+		if _, err := upstream.GetObjectByID(ctx, "1"); err != nil {
+			t.Errorf("expected upstream object to remain, got %v", err)
+		}
+	})
+
+	t.Run("get all merges local changes", func(t *testing.T) {
+		objectsList, err := overlay.GetAllObjects(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if len(objectsList) != 13 {
+			t.Fatalf("expected 13 objects, got %d", len(objectsList))
+		}
+		if objectsList[0].ID != "2" {
+			t.Errorf("expected deleted object to be hidden, got %s first", objectsList[0].ID)
+		}
+		if objectsList[5].ID != "7" || objectsList[5].Name != updated.Name {
+			t.Errorf("expected updated object in place of upstream object, got %+v", objectsList[5])
+		}
+		if objectsList[12].ID != created.ID {
+			t.Errorf("expected created object last, got %s", objectsList[12].ID)
+		}
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		if objectData, err := overlay.GetObjectByID(ctx, "7"); err != nil || objectData.Name != updated.Name {
+			t.Errorf("expected updated object, got %+v, %v", objectData, err)
+		}
+		if _, err := overlay.GetObjectByID(ctx, "1"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound for deleted object, got %v", err)
+		}
+		if _, err := overlay.UpdateObject(ctx, "1", models.ObjDataPayload{Name: "x"}); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound when updating deleted object, got %v", err)
+		}
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		objectsList, err := overlay.GetObjectsByIDs(ctx, created.ID, "1", "7", "3")
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		want := []string{created.ID, "7", "3"}
+		if len(objectsList) != len(want) {
+			t.Fatalf("expected %v, got %v", want, objectsList)
+		}
+		for i, obj := range objectsList {
+			if obj.ID != want[i] {
+				t.Errorf("expected %v, got %v", want, objectsList)
+			}
+		}
+	})
+
+	t.Run("delete unknown object", func(t *testing.T) {
+		if _, err := overlay.DeleteObject(ctx, "unknown"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("requires local store", func(t *testing.T) {
+		if _, err := store.NewOverlayStore(upstream, upstream.(*store.MemoryStore)); err != nil {
+			t.Errorf("unexpected error occured %v", err)
+		}
+		if _, err := store.NewOverlayStore(upstream, store.NewStore("http://localhost")); err == nil {
+			t.Errorf("expected error for non-local store")
+		}
+	})
+}
+
+// TestOverlayStoreRestart tests that objects deleted through OverlayStore stay hidden once it is rebuilt from the same file
+func TestOverlayStoreRestart(t *testing.T) {
+
+	ctx := context.Background()
+	upstream := store.NewMemoryStore(store.ReservedObjects())
+	path := filepath.Join(t.TempDir(), "overlay.db")
+
+	local, err := store.NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	overlay, err := store.NewOverlayStore(upstream, local)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if _, err := overlay.UpdateObject(ctx, "7", models.ObjDataPayload{Name: "Apple MacBook Pro 16 (2023)"}); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	for _, id := range []string{"1", "7"} {
+		if _, err := overlay.DeleteObject(ctx, id); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+	}
+
+	local = reopenBoltStore(t, local, path)
+	defer local.(io.Closer).Close()
+	overlay, err = store.NewOverlayStore(upstream, local)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	for _, id := range []string{"1", "7"} {
+		if _, err := overlay.GetObjectByID(ctx, id); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected ErrNotFound for deleted object %s, got %v", id, err)
+		}
+	}
+	if objectsList, _ := overlay.GetAllObjects(ctx); len(objectsList) != 11 {
+		t.Errorf("expected 11 objects, got %d", len(objectsList))
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// errors returned for refresh tokens, to be matched with errors.Is
+var (
+	// ErrRefreshTokenInvalid is returned for a refresh token that is unknown, expired or revoked
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+	// ErrRefreshTokenReused is returned, wrapped with ErrRefreshTokenInvalid, when a refresh token is exchanged a second time.
+	// Every token of its family is revoked as the token may have been stolen.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+)
+
+// RefreshTokenStore defines the operations on the refresh tokens that have been issued
+type RefreshTokenStore interface {
+	// SaveRefreshToken stores a newly issued refresh token
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	// RotateRefreshToken marks the token with the given hash as used and stores its replacement in the same family.
+	// It returns the record of the replaced token.
+	RotateRefreshToken(ctx context.Context, hash string, nextHash string, nextExpiresAt time.Time) (models.RefreshToken, error)
+	// RevokeRefreshToken revokes the token with the given hash along with every token of its family
+	RevokeRefreshToken(ctx context.Context, hash string) error
+}
+
+// MemoryRefreshTokenStore implements RefreshTokenStore in memory, expired tokens are pruned as new tokens are stored
+type MemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]models.RefreshToken
+}
+
+// NewMemoryRefreshTokenStore acts as a constructor method to create an empty refresh token store
+func NewMemoryRefreshTokenStore() RefreshTokenStore {
+	return &MemoryRefreshTokenStore{
+		tokens: make(map[string]models.RefreshToken),
+	}
+}
+
+// SaveRefreshToken stores a newly issued refresh token
+func (m *MemoryRefreshTokenStore) SaveRefreshToken(_ context.Context, token models.RefreshToken) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	m.tokens[token.Hash] = token
+
+	return nil
+}
+
+// RotateRefreshToken marks the token with the given hash as used and stores its replacement in the same family
+func (m *MemoryRefreshTokenStore) RotateRefreshToken(_ context.Context, hash string, nextHash string, nextExpiresAt time.Time) (models.RefreshToken, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[hash]
+	if !ok || time.Now().After(token.ExpiresAt) {
+		return models.RefreshToken{}, ErrRefreshTokenInvalid
+	}
+
+	if token.Used {
+		m.revokeFamily(token.Family)
+		return models.RefreshToken{}, fmt.Errorf("%w, %w", ErrRefreshTokenInvalid, ErrRefreshTokenReused)
+	}
+
+	token.Used = true
+	m.tokens[hash] = token
+
+	m.prune()
+	m.tokens[nextHash] = models.RefreshToken{
+		Hash:      nextHash,
+		Family:    token.Family,
+		Username:  token.Username,
+		ExpiresAt: nextExpiresAt,
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken revokes the token with the given hash along with every token of its family
+func (m *MemoryRefreshTokenStore) RevokeRefreshToken(_ context.Context, hash string) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[hash]
+	if !ok {
+		return ErrRefreshTokenInvalid
+	}
+
+	m.revokeFamily(token.Family)
+	return nil
+}
+
+func (m *MemoryRefreshTokenStore) revokeFamily(family string) {
+	for hash, token := range m.tokens {
+		if token.Family == family {
+			delete(m.tokens, hash)
+		}
+	}
+}
+
+// prune removes expired tokens, it must be called with the lock held
+func (m *MemoryRefreshTokenStore) prune() {
+	now := time.Now()
+	for hash, token := range m.tokens {
+		if now.After(token.ExpiresAt) {
+			delete(m.tokens, hash)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header that makes a POST request to the external API safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKey struct{}
+
+// RetryPolicy defines how calls to the external API are retried on connection errors, 429 and 5xx responses.
+// Only idempotent calls are retried, which are GET, PUT and DELETE, and POST when an idempotency key is present.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts of a call, a value below 2 disables retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts
+	MaxDelay time.Duration
+}
+
+// WithRetryPolicy sets the policy used to retry failed calls to the external API
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// WithIdempotencyKey returns a copy of ctx carrying an idempotency key that is sent along with the request to create an object,
+// so that the request can be retried without creating the object twice
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// do sends the request to the external API, retrying it as per the retry policy of the store.
+// Retries stop as soon as the next attempt cannot start before the deadline of the request context.
+func (s ObjectStore) do(req *http.Request) (*http.Response, error) {
+
+	ctx := req.Context()
+	retryable := isIdempotent(req)
+
+	for attempt := 1; ; attempt++ {
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := s.client.Do(req)
+		if !retryable || attempt >= s.retry.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := s.retry.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isIdempotent reports whether the request can be sent more than once without changing its outcome
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return req.Header.Get(IdempotencyKeyHeader) != ""
+	}
+	return false
+}
+
+// shouldRetry reports whether a call failed for a reason that may not persist
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the next attempt, growing exponentially with the attempt and randomized
+// within its upper half so that concurrent callers do not retry in lockstep
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// parseRetryAfter parses the Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// RevocationBackend persists the revoked tokens held by RevocationList, so that they survive a restart
+type RevocationBackend interface {
+	// LoadRevocations returns the revoked token IDs along with their expiry
+	LoadRevocations() (map[string]time.Time, error)
+	// SaveRevocations replaces the persisted revoked tokens
+	SaveRevocations(revoked map[string]time.Time) error
+}
+
+// RevocationList implements models.RevocationList in memory, optionally persisted by a RevocationBackend.
+// Tokens are forgotten once they expire, as they are rejected from then on regardless of revocation.
+type RevocationList struct {
+	backend RevocationBackend
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewRevocationList acts as a constructor method to create a revocation list, loading the tokens persisted by backend.
+// The list is only held in memory when backend is nil.
+func NewRevocationList(backend RevocationBackend) (models.RevocationList, error) {
+
+	revoked := make(map[string]time.Time)
+	if backend != nil {
+		loaded, err := backend.LoadRevocations()
+		if err != nil {
+			return nil, err
+		}
+		for tokenID, expiresAt := range loaded {
+			revoked[tokenID] = expiresAt
+		}
+	}
+
+	l := &RevocationList{
+		backend: backend,
+		revoked: revoked,
+	}
+	l.prune(time.Now())
+
+	return l, nil
+}
+
+// Revoke revokes the token with the given ID until it expires
+func (l *RevocationList) Revoke(tokenID string, expiresAt time.Time) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil
+	}
+
+	l.prune(now)
+	previous, existed := l.revoked[tokenID]
+	l.revoked[tokenID] = expiresAt
+
+	if l.backend != nil {
+		if err := l.backend.SaveRevocations(l.revoked); err != nil {
+			if existed {
+				l.revoked[tokenID] = previous
+			} else {
+				delete(l.revoked, tokenID)
+			}
+			return fmt.Errorf("error saving revoked token, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the token with the given ID has been revoked and has not expired yet
+func (l *RevocationList) IsRevoked(tokenID string) (bool, error) {
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	expiresAt, ok := l.revoked[tokenID]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// prune forgets the tokens that have expired, it must be called with the write lock held
+func (l *RevocationList) prune(now time.Time) {
+	for tokenID, expiresAt := range l.revoked {
+		if !expiresAt.After(now) {
+			delete(l.revoked, tokenID)
+		}
+	}
+}
+
+// FileRevocationBackend implements RevocationBackend by persisting revoked tokens in a JSON file
+type FileRevocationBackend struct {
+	path string
+}
+
+// NewFileRevocationBackend acts as a constructor method to persist revoked tokens at path, the file is created on the first revocation
+func NewFileRevocationBackend(path string) RevocationBackend {
+	return &FileRevocationBackend{path: path}
+}
+
+// LoadRevocations reads the revoked tokens from the file, no tokens are returned when it does not exist
+func (f *FileRevocationBackend) LoadRevocations() (map[string]time.Time, error) {
+
+	content, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading revocation file, %w", err)
+	}
+
+	var revoked map[string]time.Time
+	if err := json.Unmarshal(content, &revoked); err != nil {
+		return nil, fmt.Errorf("error decoding revocation file %s, %w", f.path, err)
+	}
+
+	return revoked, nil
+}
+
+// SaveRevocations replaces the file with the given revoked tokens, writing to a temporary file first
+func (f *FileRevocationBackend) SaveRevocations(revoked map[string]time.Time) error {
+
+	content, err := json.Marshal(revoked)
+	if err != nil {
+		return fmt.Errorf("error encoding revocation file, %w", err)
+	}
+
+	return writeFileAtomically(f.path, content)
+}
+
+// writeFileAtomically replaces the file at path with content, writing to a temporary file first so that a failed write leaves the file intact
+func writeFileAtomically(path string, content []byte) error {
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating directory of %s, %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s, %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s, %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s, %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s, %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing %s, %w", path, err)
+	}
+
+	return nil
+}
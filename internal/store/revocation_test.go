@@ -0,0 +1,59 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// TestRevocationList tests RevocationList with FileRevocationBackend
+func TestRevocationList(t *testing.T) {
+
+	backend := store.NewFileRevocationBackend(filepath.Join(t.TempDir(), "revoked.json"))
+
+	revoked, err := store.NewRevocationList(backend)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if err := revoked.Revoke("long-lived", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if err := revoked.Revoke("short-lived", time.Now().Add(20*time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if isRevoked, _ := revoked.IsRevoked("short-lived"); !isRevoked {
+		t.Errorf("expected short-lived token to be revoked")
+	}
+	if isRevoked, _ := revoked.IsRevoked("unknown"); isRevoked {
+		t.Errorf("expected unknown token not to be revoked")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if isRevoked, _ := revoked.IsRevoked("short-lived"); isRevoked {
+		t.Errorf("expected expired token to be forgotten")
+	}
+
+	// expired tokens are pruned from the backend on the next revocation
+	if err := revoked.Revoke("another", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	persisted, err := backend.LoadRevocations()
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if len(persisted) != 2 {
+		t.Errorf("expected 2 persisted tokens, got %v", persisted)
+	}
+
+	reloaded, err := store.NewRevocationList(backend)
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if isRevoked, _ := reloaded.IsRevoked("long-lived"); !isRevoked {
+		t.Errorf("expected revoked token to survive a restart")
+	}
+}
@@ -8,28 +8,43 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
-	"time"
 
 	"github.com/harshitrajsinha/obj-rest/internal/models"
 )
 
+// APIKeyHeader is the header that carries the API key of the external API
+const APIKeyHeader = "x-api-key"
+
 // ObjectStore implements ObjectDataAccessor to define methods to fetch data from external API
 type ObjectStore struct {
 	APIURL string
+	client *http.Client
+	retry  RetryPolicy
+
+	apiKey     string
+	collection string
 }
 
-// NewStore acts as a constructor method for dependency injection
-func NewStore(apiURL string) ObjectDataAccessor {
+// NewStore acts as a constructor method for dependency injection.
+// All the calls to external API share a single HTTP client that is configured using opts.
+func NewStore(apiURL string, opts ...Option) ObjectDataAccessor {
+	options := newClientOptions(opts...)
 	return &ObjectStore{
 		APIURL: apiURL,
+		client: newHTTPClient(options),
+		retry:  options.retry,
+
+		apiKey:     options.apiKey,
+		collection: options.collection,
 	}
 }
 
-// GetAllObjects fetches all objects from the external API
+// GetAllObjects fetches all objects from the external API, an empty collection is returned as an empty list
 func (s ObjectStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromResponse, error) {
 
-	apiURL := s.APIURL + "/objects"
+	apiURL := s.objectsURL("")
 
 	// create a new request with context
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
@@ -37,25 +52,17 @@ func (s ObjectStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromRes
 		return nil, fmt.Errorf("error creating request to fetch all objects, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending response to fetch all objects, %w", err)
+		return nil, fmt.Errorf("error sending response to fetch all objects, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return nil, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -65,8 +72,12 @@ func (s ObjectStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromRes
 		return nil, fmt.Errorf("error parsing response of all objects, %w", err)
 	}
 
-	if len(objectsList) == 0 || (len(objectsList) != 0 && objectsList[0].ID == "") {
-		return nil, errors.New("error - no data retrieved in response")
+	// an empty collection is a valid list of objects, unlike a list of objects without ID
+	if len(objectsList) != 0 && objectsList[0].ID == "" {
+		return nil, errors.New("error parsing response of all objects, objects have no id")
+	}
+	if objectsList == nil {
+		objectsList = []models.ObjDataFromResponse{}
 	}
 
 	return objectsList, nil
@@ -75,12 +86,11 @@ func (s ObjectStore) GetAllObjects(ctx context.Context) ([]models.ObjDataFromRes
 // GetObjectsByIDs fetches list of all objects based on different IDs input as query params
 func (s ObjectStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]models.ObjDataFromResponse, error) {
 
-	var str strings.Builder
-	str.WriteString("?")
+	query := url.Values{}
 	for _, id := range IDs {
-		str.WriteString(fmt.Sprintf("id=%s&", id))
+		query.Add("id", id)
 	}
-	apiURL := s.APIURL + "/objects" + str.String()
+	apiURL := s.objectsURL("?" + query.Encode())
 
 	// create a new request with context
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
@@ -88,25 +98,17 @@ func (s ObjectStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]mode
 		return nil, fmt.Errorf("error creating request to fetch objects based on IDs, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending response to fetch objects based on IDs, %w", err)
+		return nil, fmt.Errorf("error sending response to fetch objects based on IDs, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return nil, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -117,7 +119,7 @@ func (s ObjectStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]mode
 	}
 
 	if len(objectsList) == 0 || (len(objectsList) != 0 && objectsList[0].ID == "") {
-		return nil, errors.New("error - no data retrieved in response")
+		return nil, fmt.Errorf("%w, no data retrieved in response", ErrNotFound)
 	}
 
 	return objectsList, nil
@@ -127,7 +129,7 @@ func (s ObjectStore) GetObjectsByIDs(ctx context.Context, IDs ...string) ([]mode
 func (s ObjectStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDataFromResponse, error) {
 
 	var objectData models.ObjDataFromResponse
-	apiURL := s.APIURL + "/objects/" + ID
+	apiURL := s.objectURL(ID)
 
 	// create a new request with context
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
@@ -135,25 +137,17 @@ func (s ObjectStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDa
 		return objectData, fmt.Errorf("error creating request to fetch object based on ID, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return objectData, fmt.Errorf("error sending response to fetch object based on ID, %w", err)
+		return objectData, fmt.Errorf("error sending response to fetch object based on ID, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return objectData, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return objectData, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -164,7 +158,7 @@ func (s ObjectStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDa
 	}
 
 	if objectData.ID == "" {
-		return objectData, errors.New("error - no data retrieved in response")
+		return objectData, fmt.Errorf("%w, no data retrieved in response", ErrNotFound)
 	}
 
 	return objectData, nil
@@ -174,7 +168,7 @@ func (s ObjectStore) GetObjectByID(ctx context.Context, ID string) (models.ObjDa
 func (s ObjectStore) CreateNewObject(ctx context.Context, objPayload models.ObjDataPayload) (models.NewObj, error) {
 
 	var objectData models.NewObj
-	apiURL := s.APIURL + "/objects"
+	apiURL := s.objectsURL("")
 
 	// encode into JSON string
 	payloadToSend, err := json.Marshal(objPayload)
@@ -188,25 +182,20 @@ func (s ObjectStore) CreateNewObject(ctx context.Context, objPayload models.ObjD
 		return objectData, fmt.Errorf("error creating request to create new object, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
+	s.setHeaders(req)
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
 	}
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return objectData, fmt.Errorf("error sending response to create new object, %w", err)
+		return objectData, fmt.Errorf("error sending response to create new object, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return objectData, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return objectData, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -217,7 +206,7 @@ func (s ObjectStore) CreateNewObject(ctx context.Context, objPayload models.ObjD
 	}
 
 	if objectData.ID == "" {
-		return objectData, errors.New("error - no data retrieved in response")
+		return objectData, fmt.Errorf("%w, no data retrieved in response", ErrNotFound)
 	}
 
 	return objectData, nil
@@ -227,7 +216,7 @@ func (s ObjectStore) CreateNewObject(ctx context.Context, objPayload models.ObjD
 func (s ObjectStore) UpdateObject(ctx context.Context, objID string, objPayload models.ObjDataPayload) (models.NewObj, error) {
 
 	var objectData models.NewObj
-	apiURL := s.APIURL + "/objects/" + objID
+	apiURL := s.objectURL(objID)
 
 	// encode into JSON string
 	payloadToSend, err := json.Marshal(objPayload)
@@ -241,25 +230,17 @@ func (s ObjectStore) UpdateObject(ctx context.Context, objID string, objPayload
 		return objectData, fmt.Errorf("error creating request to update object, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return objectData, fmt.Errorf("error sending response to update object, %w", err)
+		return objectData, fmt.Errorf("error sending response to update object, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return objectData, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return objectData, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -270,7 +251,7 @@ func (s ObjectStore) UpdateObject(ctx context.Context, objID string, objPayload
 	}
 
 	if objectData.ID == "" {
-		return objectData, errors.New("error - no data retrieved in response")
+		return objectData, fmt.Errorf("%w, no data retrieved in response", ErrNotFound)
 	}
 
 	return objectData, nil
@@ -280,7 +261,7 @@ func (s ObjectStore) UpdateObject(ctx context.Context, objID string, objPayload
 func (s ObjectStore) UpdateObjectPartially(ctx context.Context, objID string, objPayload models.ObjDataPayload) (models.NewObj, error) {
 
 	var objectData models.NewObj
-	apiURL := s.APIURL + "/objects/" + objID
+	apiURL := s.objectURL(objID)
 
 	// encode into JSON string
 	payloadToSend, err := json.Marshal(objPayload)
@@ -294,25 +275,17 @@ func (s ObjectStore) UpdateObjectPartially(ctx context.Context, objID string, ob
 		return objectData, fmt.Errorf("error creating request to partially update object, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return objectData, fmt.Errorf("error sending response to partially update object, %w", err)
+		return objectData, fmt.Errorf("error sending response to partially update object, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return objectData, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return objectData, newUpstreamStatusError(resp)
 	}
 
 	// parse response
@@ -323,56 +296,83 @@ func (s ObjectStore) UpdateObjectPartially(ctx context.Context, objID string, ob
 	}
 
 	if objectData.ID == "" {
-		return objectData, errors.New("error - no data retrieved in response")
+		return objectData, fmt.Errorf("%w, no data retrieved in response", ErrNotFound)
 	}
 
 	return objectData, nil
 }
 
 // DeleteObject deletes a created object
-func (s ObjectStore) DeleteObject(ctx context.Context, objID string) (map[string]string, error) {
+func (s ObjectStore) DeleteObject(ctx context.Context, objID string) (models.DeleteResult, error) {
 
-	var response map[string]string
+	var result models.DeleteResult
 
-	apiURL := s.APIURL + "/objects/" + objID
+	apiURL := s.objectURL(objID)
 
 	// create a new request with context
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request to delete object, %w", err)
+		return result, fmt.Errorf("error creating request to delete object, %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	// create new client
-	newClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    100,
-			IdleConnTimeout: 90 * time.Second,
-		},
-	}
+	s.setHeaders(req)
 
 	// send request and get response
-	resp, err := newClient.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending response to delete object, %w", err)
+		return result, fmt.Errorf("error sending response to delete object, %w", transportError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return result, newUpstreamStatusError(resp)
 	}
 
 	// parse response
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	err = json.NewDecoder(resp.Body).Decode(&result)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing response of updated object, %w", err)
+		return result, fmt.Errorf("error parsing response of deleted object, %w", err)
 	}
 
-	value, ok := response["message"]
-	if !ok || !strings.Contains(value, "has been deleted") {
-		return nil, errors.New("the response of delete object API is not as expected")
+	if !strings.Contains(result.Message, "has been deleted") {
+		return result, errors.New("the response of delete object API is not as expected")
 	}
+	result.ID = objID
+
+	return result, nil
+}
 
-	return response, nil
+// transportError marks an error sending a request to the external API as ErrUpstreamUnavailable,
+// unless the request was cancelled by the caller, which says nothing about the availability of the external API
+func transportError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+}
+
+// objectURL returns the URL of the object with the given ID, which is escaped so that it cannot point the request outside the collection
+func (s ObjectStore) objectURL(ID string) string {
+	escaped := url.PathEscape(ID)
+	// dot segments are resolved by servers along the way, so their dots are escaped as well
+	if ID == "." || ID == ".." {
+		escaped = strings.ReplaceAll(ID, ".", "%2E")
+	}
+	return s.objectsURL("/" + escaped)
+}
+
+// objectsURL returns the URL of the objects of the configured collection, or of the public objects, followed by suffix
+func (s ObjectStore) objectsURL(suffix string) string {
+	if s.collection != "" {
+		return s.APIURL + "/collections/" + url.PathEscape(s.collection) + "/objects" + suffix
+	}
+	return s.APIURL + "/objects" + suffix
+}
+
+// setHeaders sets the headers sent with every call to the external API
+func (s ObjectStore) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set(APIKeyHeader, s.apiKey)
+	}
 }
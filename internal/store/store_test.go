@@ -0,0 +1,395 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// roundTripFunc implements http.RoundTripper to stub the external API
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls the stub function
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// jsonResponse builds a response of the external API with the given status and body
+func jsonResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// TestGetAllObjects tests GetAllObjects of ObjectStore
+func TestGetAllObjects(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCount int
+		wantErr   bool
+	}{
+		{"objects", `[{"id": "1", "name": "One"}, {"id": "2", "name": "Two"}]`, 2, false},
+		{"empty collection", `[]`, 0, false},
+		{"objects without id", `[{"name": "One"}]`, 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(http.StatusOK, tc.body), nil
+			})
+
+			objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+			objects, err := objStore.GetAllObjects(context.Background())
+			if tc.wantErr {
+				if err == nil || errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected parsing error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if objects == nil || len(objects) != tc.wantCount {
+				t.Errorf("expected %d objects, got %v", tc.wantCount, objects)
+			}
+		})
+	}
+}
+
+// TestGetObjectsByIDs tests GetObjectsByIDs of ObjectStore
+func TestGetObjectsByIDs(t *testing.T) {
+
+	var gotQuery string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotQuery = req.URL.RawQuery
+		return jsonResponse(http.StatusOK, `[{"id": "1", "name": "One"}, {"id": "a&b", "name": "Escaped"}]`), nil
+	})
+
+	objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+	objects, err := objStore.GetObjectsByIDs(context.Background(), "1", "a&b")
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if gotQuery != "id=1&id=a%26b" {
+		t.Errorf("expected escaped query id=1&id=a%%26b, got %s", gotQuery)
+	}
+	if len(objects) != 2 {
+		t.Errorf("expected 2 objects, got %d", len(objects))
+	}
+}
+
+// TestDeleteObject tests DeleteObject of ObjectStore
+func TestDeleteObject(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		code    int
+		body    string
+		wantErr bool
+	}{
+		{"deleted", http.StatusOK, `{"message": "Object with id = 7, has been deleted."}`, false},
+		{"unknown object", http.StatusNotFound, `{"error": "Object with id = 7 doesn't exist."}`, true},
+		{"unexpected body", http.StatusOK, `{}`, true},
+		{"unexpected message", http.StatusOK, `{"message": "Object with id = 7 is scheduled for review."}`, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodDelete || req.URL.Path != "/objects/7" {
+					t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				}
+				return jsonResponse(tc.code, tc.body), nil
+			})
+
+			objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+			result, err := objStore.DeleteObject(context.Background(), "7")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && result != (models.DeleteResult{ID: "7", Message: "Object with id = 7, has been deleted."}) {
+				t.Errorf("unexpected result %v", result)
+			}
+		})
+	}
+}
+
+// TestAPIKeyAndCollection tests that every call to the external API is authenticated and sent to the configured collection
+func TestAPIKeyAndCollection(t *testing.T) {
+
+	var gotRequests []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if key := req.Header.Get(store.APIKeyHeader); key != "secret" {
+			t.Errorf("expected API key secret, got %q", key)
+		}
+		gotRequests = append(gotRequests, req.Method+" "+req.URL.EscapedPath())
+		switch req.Method {
+		case http.MethodGet:
+			return jsonResponse(http.StatusOK, `[{"id": "7", "name": "Seven"}]`), nil
+		case http.MethodDelete:
+			return jsonResponse(http.StatusOK, `{"message": "Object with id = 7, has been deleted."}`), nil
+		}
+		return jsonResponse(http.StatusOK, `{"id": "7", "name": "Seven"}`), nil
+	})
+
+	objStore := store.NewStore("https://api.example.test",
+		store.WithTransport(transport),
+		store.WithAPIKey("secret"),
+		store.WithCollection("team objects"),
+	)
+
+	ctx := context.Background()
+	objStore.GetAllObjects(ctx)
+	objStore.GetObjectsByIDs(ctx, "7")
+	objStore.CreateNewObject(ctx, models.ObjDataPayload{Name: "Seven"})
+	objStore.UpdateObject(ctx, "7", models.ObjDataPayload{Name: "Seven"})
+	objStore.DeleteObject(ctx, "7")
+
+	want := []string{
+		"GET /collections/team%20objects/objects",
+		"GET /collections/team%20objects/objects",
+		"POST /collections/team%20objects/objects",
+		"PUT /collections/team%20objects/objects/7",
+		"DELETE /collections/team%20objects/objects/7",
+	}
+	if len(gotRequests) != len(want) {
+		t.Fatalf("expected requests %v, got %v", want, gotRequests)
+	}
+	for i := range want {
+		if gotRequests[i] != want[i] {
+			t.Errorf("expected request %s, got %s", want[i], gotRequests[i])
+		}
+	}
+}
+
+// TestObjectIDEscaped tests that object IDs cannot point calls to the external API outside the configured collection
+func TestObjectIDEscaped(t *testing.T) {
+
+	var gotPaths []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotPaths = append(gotPaths, req.URL.EscapedPath())
+		if req.Method == http.MethodDelete {
+			return jsonResponse(http.StatusOK, `{"message": "Object with id = 7, has been deleted."}`), nil
+		}
+		return jsonResponse(http.StatusOK, `{"id": "7", "name": "Seven"}`), nil
+	})
+
+	objStore := store.NewStore("https://api.example.test",
+		store.WithTransport(transport),
+		store.WithAPIKey("secret"),
+		store.WithCollection("team-a"),
+	)
+
+	ctx := context.Background()
+	id := "../../collections/team-b/objects/7"
+	objStore.GetObjectByID(ctx, id)
+	objStore.UpdateObject(ctx, id, models.ObjDataPayload{Name: "Seven"})
+	objStore.UpdateObjectPartially(ctx, id, models.ObjDataPayload{Name: "Seven"})
+	objStore.DeleteObject(ctx, id)
+
+	objStore.GetObjectByID(ctx, "..")
+
+	want := []string{
+		"/collections/team-a/objects/..%2F..%2Fcollections%2Fteam-b%2Fobjects%2F7",
+		"/collections/team-a/objects/..%2F..%2Fcollections%2Fteam-b%2Fobjects%2F7",
+		"/collections/team-a/objects/..%2F..%2Fcollections%2Fteam-b%2Fobjects%2F7",
+		"/collections/team-a/objects/..%2F..%2Fcollections%2Fteam-b%2Fobjects%2F7",
+		"/collections/team-a/objects/%2E%2E",
+	}
+	if len(gotPaths) != len(want) {
+		t.Fatalf("expected requests %v, got %v", want, gotPaths)
+	}
+	for i := range want {
+		if gotPaths[i] != want[i] {
+			t.Errorf("expected request path %s, got %s", want[i], gotPaths[i])
+		}
+	}
+}
+
+// TestRetryPolicy tests retries of calls to the external API
+func TestRetryPolicy(t *testing.T) {
+
+	policy := store.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	payload := models.ObjDataPayload{Name: "Apple MacBook Pro 16"}
+
+	t.Run("GET retried on 503 and 429", func(t *testing.T) {
+		attempts := 0
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			switch attempts {
+			case 1:
+				return jsonResponse(http.StatusServiceUnavailable, `{}`), nil
+			case 2:
+				resp := jsonResponse(http.StatusTooManyRequests, `{}`)
+				resp.Header.Set("Retry-After", "0")
+				return resp, nil
+			}
+			return jsonResponse(http.StatusOK, `{"id": "1", "name": "One"}`), nil
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport), store.WithRetryPolicy(policy))
+		if _, err := objStore.GetObjectByID(context.Background(), "1"); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("GET not retried on 404", func(t *testing.T) {
+		attempts := 0
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return jsonResponse(http.StatusNotFound, `{}`), nil
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport), store.WithRetryPolicy(policy))
+		if _, err := objStore.GetObjectByID(context.Background(), "1"); err == nil {
+			t.Fatalf("expected error for unknown object")
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("POST without idempotency key not retried", func(t *testing.T) {
+		attempts := 0
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset")
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport), store.WithRetryPolicy(policy))
+		if _, err := objStore.CreateNewObject(context.Background(), payload); err == nil {
+			t.Fatalf("expected error for failed connection")
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("POST with idempotency key retried with same body", func(t *testing.T) {
+		var bodies []string
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			if req.Header.Get(store.IdempotencyKeyHeader) != "key-1" {
+				t.Errorf("expected idempotency key header, got %q", req.Header.Get(store.IdempotencyKeyHeader))
+			}
+			if len(bodies) == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return jsonResponse(http.StatusOK, `{"id": "abc", "name": "Apple MacBook Pro 16"}`), nil
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport), store.WithRetryPolicy(policy))
+		ctx := store.WithIdempotencyKey(context.Background(), "key-1")
+		if _, err := objStore.CreateNewObject(ctx, payload); err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if len(bodies) != 2 || bodies[0] != bodies[1] {
+			t.Errorf("expected 2 attempts with same body, got %v", bodies)
+		}
+	})
+
+	t.Run("Retry-After beyond deadline not waited for", func(t *testing.T) {
+		attempts := 0
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			resp := jsonResponse(http.StatusServiceUnavailable, `{}`)
+			resp.Header.Set("Retry-After", "60")
+			return resp, nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport), store.WithRetryPolicy(policy))
+		if _, err := objStore.GetAllObjects(ctx); err == nil {
+			t.Fatalf("expected error for unavailable upstream")
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+}
+
+// TestUpstreamStatusError tests the errors returned for unexpected responses of the external API
+func TestUpstreamStatusError(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		code       int
+		wantTarget error
+	}{
+		{"not found", http.StatusNotFound, store.ErrNotFound},
+		{"reserved object", http.StatusMethodNotAllowed, store.ErrConflict},
+		{"rate limited", http.StatusTooManyRequests, store.ErrUpstreamUnavailable},
+		{"server error", http.StatusBadGateway, store.ErrUpstreamUnavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(tc.code, `{"error": "failed"}`), nil
+			})
+
+			objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+			_, err := objStore.UpdateObject(context.Background(), "7", models.ObjDataPayload{Name: "Updated"})
+			if !errors.Is(err, tc.wantTarget) {
+				t.Errorf("expected error to match %v, got %v", tc.wantTarget, err)
+			}
+
+			var statusErr *store.UpstreamStatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected UpstreamStatusError, got %T", err)
+			}
+			if statusErr.StatusCode != tc.code || statusErr.Body != `{"error": "failed"}` {
+				t.Errorf("unexpected status error %v", statusErr)
+			}
+		})
+	}
+
+	t.Run("connection error", func(t *testing.T) {
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+		_, err := objStore.GetAllObjects(context.Background())
+		if !errors.Is(err, store.ErrUpstreamUnavailable) {
+			t.Errorf("expected error to match %v, got %v", store.ErrUpstreamUnavailable, err)
+		}
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{}`), nil
+		})
+
+		objStore := store.NewStore("https://api.example.test", store.WithTransport(transport))
+
+		_, err := objStore.GetObjectByID(context.Background(), "99")
+		if !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("expected error to match %v, got %v", store.ErrNotFound, err)
+		}
+	})
+}
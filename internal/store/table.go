@@ -0,0 +1,311 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// timestampLayout matches the timestamps generated by external API, such as 2022-11-21T20:06:23.986Z
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// objectTable holds objects in memory for the stores that do not depend on external API.
+// Objects are kept in the order in which they were created and every object handed out is a copy.
+// It also holds the tombstones of OverlayStore, which hide deleted objects of external API.
+type objectTable struct {
+	mu         sync.RWMutex
+	objects    map[string]models.NewObj
+	order      []string
+	tombstones map[string]bool
+
+	// persist is called with each change, the change is reverted when it fails
+	persist func(change tableChange) error
+}
+
+// tableChange represents a change of a single object held by objectTable
+type tableChange struct {
+	// put is the object that has been created or replaced
+	put *models.NewObj
+	// deleted is the ID of the object that has been removed
+	deleted string
+	// tombstone is the ID of the object that has been hidden
+	tombstone string
+}
+
+func newObjectTable(objects []models.NewObj) *objectTable {
+
+	t := &objectTable{
+		objects:    make(map[string]models.NewObj, len(objects)),
+		tombstones: make(map[string]bool),
+	}
+
+	for _, obj := range objects {
+		if _, ok := t.objects[obj.ID]; !ok {
+			t.order = append(t.order, obj.ID)
+		}
+		t.objects[obj.ID] = copyObject(obj)
+	}
+
+	return t
+}
+
+// list returns every object
+func (t *objectTable) list() []models.ObjDataFromResponse {
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	objectsList := make([]models.ObjDataFromResponse, 0, len(t.order))
+	for _, id := range t.order {
+		objectsList = append(objectsList, toObjData(t.objects[id]))
+	}
+
+	return objectsList
+}
+
+// get returns the object with the given ID
+func (t *objectTable) get(id string) (models.ObjDataFromResponse, error) {
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	obj, ok := t.objects[id]
+	if !ok {
+		return models.ObjDataFromResponse{}, fmt.Errorf("%w, no object with id %s", ErrNotFound, id)
+	}
+
+	return toObjData(obj), nil
+}
+
+// getMany returns the objects with the given IDs in the requested order, skipping unknown IDs
+func (t *objectTable) getMany(ids []string) ([]models.ObjDataFromResponse, error) {
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var objectsList []models.ObjDataFromResponse
+	for _, id := range ids {
+		if obj, ok := t.objects[id]; ok {
+			objectsList = append(objectsList, toObjData(obj))
+		}
+	}
+
+	if len(objectsList) == 0 {
+		return nil, fmt.Errorf("%w, no object with requested ids", ErrNotFound)
+	}
+
+	return objectsList, nil
+}
+
+// create adds a new object with a generated ID
+func (t *objectTable) create(payload models.ObjDataPayload) (models.NewObj, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	obj := models.NewObj{
+		ID:        uuid.NewString(),
+		CreatedAt: time.Now().UTC().Format(timestampLayout),
+		Name:      payload.Name,
+		Data:      copyData(payload.Data),
+	}
+
+	t.objects[obj.ID] = obj
+	t.order = append(t.order, obj.ID)
+
+	if err := t.save(tableChange{put: &obj}); err != nil {
+		delete(t.objects, obj.ID)
+		t.order = t.order[:len(t.order)-1]
+		return models.NewObj{}, err
+	}
+
+	return copyObject(obj), nil
+}
+
+// update replaces the name and data of an object, or only the fields present in payload when partial is set
+func (t *objectTable) update(id string, payload models.ObjDataPayload, partial bool) (models.NewObj, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.objects[id]
+	if !ok {
+		return models.NewObj{}, fmt.Errorf("%w, no object with id %s", ErrNotFound, id)
+	}
+
+	obj := previous
+	if !partial || payload.Name != "" {
+		obj.Name = payload.Name
+	}
+	if !partial || payload.Data != nil {
+		obj.Data = copyData(payload.Data)
+	}
+	obj.UpdatedAt = time.Now().UTC().Format(timestampLayout)
+
+	t.objects[id] = obj
+	if err := t.save(tableChange{put: &obj}); err != nil {
+		t.objects[id] = previous
+		return models.NewObj{}, err
+	}
+
+	return copyObject(obj), nil
+}
+
+// put adds or replaces an object as given, keeping its position when it already exists
+func (t *objectTable) put(obj models.NewObj) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, existed := t.objects[obj.ID]
+	obj = copyObject(obj)
+	t.objects[obj.ID] = obj
+	if !existed {
+		t.order = append(t.order, obj.ID)
+	}
+
+	if err := t.save(tableChange{put: &obj}); err != nil {
+		if existed {
+			t.objects[obj.ID] = previous
+		} else {
+			delete(t.objects, obj.ID)
+			t.order = t.order[:len(t.order)-1]
+		}
+		return err
+	}
+
+	return nil
+}
+
+// delete removes an object
+func (t *objectTable) delete(id string) (models.DeleteResult, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.objects[id]
+	if !ok {
+		return models.DeleteResult{}, fmt.Errorf("%w, no object with id %s", ErrNotFound, id)
+	}
+
+	previousOrder := t.remove(id)
+
+	if err := t.save(tableChange{deleted: id}); err != nil {
+		t.objects[id] = previous
+		t.order = previousOrder
+		return models.DeleteResult{}, err
+	}
+
+	return models.DeleteResult{
+		ID:      id,
+		Message: fmt.Sprintf("Object with id = %s has been deleted.", id),
+	}, nil
+}
+
+// bury removes the object with the given ID, when it exists, and records a tombstone for it in the same change
+func (t *objectTable) bury(id string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, existed := t.objects[id]
+	previousOrder := t.order
+	if existed {
+		previousOrder = t.remove(id)
+	}
+	t.tombstones[id] = true
+
+	change := tableChange{tombstone: id}
+	if existed {
+		change.deleted = id
+	}
+	if err := t.save(change); err != nil {
+		if existed {
+			t.objects[id] = previous
+			t.order = previousOrder
+		}
+		delete(t.tombstones, id)
+		return err
+	}
+
+	return nil
+}
+
+// buried reports whether a tombstone has been recorded for the object with the given ID
+func (t *objectTable) buried(id string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tombstones[id]
+}
+
+// remove deletes an object and returns the previous order, to be restored when the change cannot be saved.
+// It must be called with the write lock held.
+func (t *objectTable) remove(id string) []string {
+
+	previousOrder := t.order
+	t.order = make([]string, 0, len(previousOrder))
+	for _, objID := range previousOrder {
+		if objID != id {
+			t.order = append(t.order, objID)
+		}
+	}
+	delete(t.objects, id)
+
+	return previousOrder
+}
+
+// save hands a change to persist, it must be called with the write lock held
+func (t *objectTable) save(change tableChange) error {
+
+	if t.persist == nil {
+		return nil
+	}
+
+	return t.persist(change)
+}
+
+func toObjData(obj models.NewObj) models.ObjDataFromResponse {
+	return models.ObjDataFromResponse{
+		ID:   obj.ID,
+		Name: obj.Name,
+		Data: copyData(obj.Data),
+	}
+}
+
+func copyObject(obj models.NewObj) models.NewObj {
+	obj.Data = copyData(obj.Data)
+	return obj
+}
+
+// copyData deep copies the data of an object, which holds values decoded from JSON
+func copyData(data map[string]interface{}) map[string]interface{} {
+
+	if data == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		copied[key] = copyValue(value)
+	}
+
+	return copied
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyData(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	}
+	return value
+}
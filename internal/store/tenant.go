@@ -0,0 +1,69 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnknownTenant is returned when no store has been configured for the tenant of a request
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// Resolver selects the store that serves the objects of a tenant
+type Resolver interface {
+	Resolve(tenant string) (ObjectDataAccessor, error)
+}
+
+// TenantResolver implements Resolver with a fixed store for each tenant and a default store for requests without a tenant
+type TenantResolver struct {
+	defaultStore ObjectDataAccessor
+	tenantStores map[string]ObjectDataAccessor
+}
+
+// NewTenantResolver acts as a constructor method to resolve the stores of tenants, defaultStore serves requests without a tenant
+func NewTenantResolver(defaultStore ObjectDataAccessor, tenantStores map[string]ObjectDataAccessor) Resolver {
+
+	stores := make(map[string]ObjectDataAccessor, len(tenantStores))
+	for tenant, tenantStore := range tenantStores {
+		stores[tenant] = tenantStore
+	}
+
+	return &TenantResolver{
+		defaultStore: defaultStore,
+		tenantStores: stores,
+	}
+}
+
+// Resolve returns the store of tenant, or the default store when tenant is empty
+func (t *TenantResolver) Resolve(tenant string) (ObjectDataAccessor, error) {
+
+	if tenant == "" {
+		return t.defaultStore, nil
+	}
+
+	tenantStore, ok := t.tenantStores[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTenant, tenant)
+	}
+
+	return tenantStore, nil
+}
+
+// Close closes the stores that hold an open file, such as BoltStore
+func (t *TenantResolver) Close() error {
+
+	errs := []error{closeStore(t.defaultStore)}
+	for _, tenantStore := range t.tenantStores {
+		errs = append(errs, closeStore(tenantStore))
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeStore closes a store when it holds an open file
+func closeStore(objStore ObjectDataAccessor) error {
+	if closer, ok := objStore.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+)
+
+// ErrUserNotFound is returned when no user exists with the requested username
+var ErrUserNotFound = errors.New("user not found")
+
+// UserStore defines the operations on the users that are allowed to login
+type UserStore interface {
+	GetUser(ctx context.Context, username string) (models.User, error)
+}
+
+// FileUserStore implements UserStore with users read from a JSON file, passwords are stored as bcrypt hashes
+type FileUserStore struct {
+	users map[string]models.User
+}
+
+// NewFileUserStore acts as a constructor method to load and validate the users listed in a JSON file
+func NewFileUserStore(path string) (UserStore, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading users file, %w", err)
+	}
+
+	var usersList []models.User
+	if err := json.Unmarshal(content, &usersList); err != nil {
+		return nil, fmt.Errorf("error decoding users file %s, %w", path, err)
+	}
+
+	users := make(map[string]models.User, len(usersList))
+	for _, user := range usersList {
+		if user.Username == "" {
+			return nil, fmt.Errorf("invalid users file %s, username is missing", path)
+		}
+		if _, ok := users[user.Username]; ok {
+			return nil, fmt.Errorf("invalid users file %s, duplicate username %s", path, user.Username)
+		}
+		if user.Role != "admin" && user.Role != "member" {
+			return nil, fmt.Errorf("invalid users file %s, unknown role %q of user %s", path, user.Role, user.Username)
+		}
+		if _, err := bcrypt.Cost([]byte(user.PasswordHash)); err != nil {
+			return nil, fmt.Errorf("invalid users file %s, password of user %s is not a bcrypt hash, %w", path, user.Username, err)
+		}
+		users[user.Username] = user
+	}
+
+	return &FileUserStore{users: users}, nil
+}
+
+// GetUser returns the user with the given username
+func (s *FileUserStore) GetUser(_ context.Context, username string) (models.User, error) {
+
+	user, ok := s.users[username]
+	if !ok {
+		return models.User{}, fmt.Errorf("%w, %s", ErrUserNotFound, username)
+	}
+
+	return user, nil
+}
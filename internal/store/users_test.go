@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// TestFileUserStore tests loading the users allowed to login from a JSON file
+func TestFileUserStore(t *testing.T) {
+
+	t.Run("example users file", func(t *testing.T) {
+		users, err := store.NewFileUserStore(filepath.Join("..", "..", "users.example.json"))
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+
+		user, err := users.GetUser(context.Background(), "alice")
+		if err != nil {
+			t.Fatalf("unexpected error occured %v", err)
+		}
+		if user.Role != "admin" || user.Tenant != "team-a" {
+			t.Errorf("expected role and tenant of alice, got %s %s", user.Role, user.Tenant)
+		}
+
+		if _, err := users.GetUser(context.Background(), "mallory"); !errors.Is(err, store.ErrUserNotFound) {
+			t.Errorf("expected error as %v, got %v", store.ErrUserNotFound, err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing username", `[{"password_hash": "$2a$10$zujq.Uw1ogtqpAIhbpP.ROJ6gJBnO5ky3HKoDUWdymjQQhXy98LZy", "role": "member"}]`},
+		{"unknown role", `[{"username": "bob", "password_hash": "$2a$10$zujq.Uw1ogtqpAIhbpP.ROJ6gJBnO5ky3HKoDUWdymjQQhXy98LZy", "role": "owner"}]`},
+		{"plain password", `[{"username": "bob", "password_hash": "bob", "role": "member"}]`},
+		{"invalid JSON", `[{"username": "bob"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "users.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("unexpected error occured %v", err)
+			}
+			if _, err := store.NewFileUserStore(path); err == nil {
+				t.Errorf("expected error loading users file")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := store.NewFileUserStore(filepath.Join(t.TempDir(), "users.json")); err == nil {
+			t.Errorf("expected error loading users file")
+		}
+	})
+}
@@ -4,10 +4,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,7 +19,9 @@ import (
 
 	"github.com/harshitrajsinha/obj-rest/config"
 	v1 "github.com/harshitrajsinha/obj-rest/internal/api/v1"
+	"github.com/harshitrajsinha/obj-rest/internal/handler"
 	"github.com/harshitrajsinha/obj-rest/internal/middleware"
+	"github.com/harshitrajsinha/obj-rest/internal/models"
 	"github.com/harshitrajsinha/obj-rest/internal/store"
 )
 
@@ -39,14 +44,43 @@ func main() {
 
 	cfg := config.Load()
 
-	storeClient := store.NewStore(cfg.BaseAPIURL)
+	stores, err := newStoreResolver(cfg)
+	if err != nil {
+		log.Fatalf("error creating store, %v", err)
+	}
+
+	users, err := store.NewFileUserStore(cfg.UsersFile)
+	if err != nil {
+		log.Fatalf("error loading users, %v. users.example.json shows the format of the users file", err)
+	}
+	var revocationBackend store.RevocationBackend
+	if cfg.RevocationFile != "" {
+		revocationBackend = store.NewFileRevocationBackend(cfg.RevocationFile)
+	}
+	revoked, err := store.NewRevocationList(revocationBackend)
+	if err != nil {
+		log.Fatalf("error loading revoked tokens, %v", err)
+	}
+
+	keyring, err := newKeyring(cfg)
+	if err != nil {
+		log.Fatalf("error loading signing keys, %v", err)
+	}
+	go reloadKeyringOnSIGHUP(cfg, keyring)
+
+	loginHandler := handler.NewLoginHandler(users, store.NewMemoryRefreshTokenStore(), revoked, keyring, handler.LoginSettings{
+		MaxFailures:     cfg.LoginMaxFailures,
+		LockoutDuration: cfg.LoginLockoutDuration,
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	})
 
 	mux := http.NewServeMux()
 
 	// register routes
-	v1.RegisterV1Routes(mux, storeClient, cfg.AuthSecretKey)
+	v1.RegisterV1Routes(mux, stores, loginHandler, keyring, revoked)
 
-	muxWithLogs := middleware.LoggingMiddleware(mux)
+	muxWithLogs := middleware.LoggingMiddleware(middleware.CacheStatusMiddleware(mux))
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -77,4 +111,124 @@ func main() {
 	if err := server.Shutdown(ctxWithTimeout); err != nil {
 		log.Fatalf("error shutting down server gracefully, %v", err)
 	}
+
+	if closer, ok := stores.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("error closing stores, %v", err)
+		}
+	}
+}
+
+// newKeyring loads the keys that sign and verify auth tokens from the keyring file, or the single signing key selected in config
+func newKeyring(cfg *config.Config) (*models.Keyring, error) {
+
+	if cfg.AuthKeyringFile != "" {
+		return models.ReadKeyringFile(cfg.AuthKeyringFile)
+	}
+
+	var signingKey *models.SigningKey
+	var err error
+	if cfg.AuthSigningAlg == models.AlgHS256 {
+		signingKey, err = models.NewHMACKey(cfg.AuthKeyID, cfg.AuthSecretKey)
+	} else {
+		signingKey, err = models.LoadSigningKey(cfg.AuthKeyID, cfg.AuthSigningAlg, cfg.AuthSigningKeyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewKeyring(signingKey)
+}
+
+// reloadKeyringOnSIGHUP loads the signing keys again whenever the process receives SIGHUP, keeping the previous keys when loading fails
+func reloadKeyringOnSIGHUP(cfg *config.Config, keyring *models.Keyring) {
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	for range reload {
+		loaded, err := newKeyring(cfg)
+		if err != nil {
+			log.Printf("error reloading signing keys, keeping current keys, %v", err)
+			continue
+		}
+		keyring.Replace(loaded)
+		log.Printf("reloaded signing keys, signing with key %s", keyring.Current().ID)
+	}
+}
+
+// newStoreResolver creates the default store and a store for each tenant, which uses the collection of the tenant
+// for external API and a separate file or memory for local backends
+func newStoreResolver(cfg *config.Config) (store.Resolver, error) {
+
+	defaultStore, err := newStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantStores := make(map[string]store.ObjectDataAccessor, len(cfg.TenantCollections))
+	for tenant, collection := range cfg.TenantCollections {
+		tenantCfg := *cfg
+		tenantCfg.UpstreamCollection = collection
+
+		ext := filepath.Ext(cfg.StoreFilePath)
+		tenantCfg.StoreFilePath = strings.TrimSuffix(cfg.StoreFilePath, ext) + "." + collection + ext
+
+		tenantStores[tenant], err = newStore(&tenantCfg)
+		if err != nil {
+			return nil, fmt.Errorf("error creating store of tenant %s, %w", tenant, err)
+		}
+	}
+
+	return store.NewTenantResolver(defaultStore, tenantStores), nil
+}
+
+// newStore creates the store selected in config
+func newStore(cfg *config.Config) (store.ObjectDataAccessor, error) {
+
+	switch cfg.StoreBackend {
+	case config.StoreBackendFile:
+		return store.NewBoltStore(cfg.StoreFilePath)
+	case config.StoreBackendMemory:
+		if cfg.StoreSeedFile != "" {
+			return store.NewMemoryStoreFromFile(cfg.StoreSeedFile)
+		}
+		return store.NewMemoryStore(store.ReservedObjects()), nil
+	case config.StoreBackendOverlay:
+		localStore, err := store.NewBoltStore(cfg.StoreFilePath)
+		if err != nil {
+			return nil, err
+		}
+		return store.NewOverlayStore(newUpstreamStore(cfg), localStore)
+	}
+
+	return newUpstreamStore(cfg), nil
+}
+
+// newUpstreamStore creates the store calling external API along with its circuit breaker and cache
+func newUpstreamStore(cfg *config.Config) store.ObjectDataAccessor {
+
+	storeClient := store.NewStore(cfg.BaseAPIURL,
+		store.WithTimeout(cfg.UpstreamTimeout),
+		store.WithMaxIdleConnsPerHost(cfg.UpstreamMaxIdleConnsPerHost),
+		store.WithRetryPolicy(store.RetryPolicy{
+			MaxAttempts: cfg.UpstreamMaxAttempts,
+			BaseDelay:   cfg.UpstreamRetryBaseDelay,
+			MaxDelay:    cfg.UpstreamRetryMaxDelay,
+		}),
+		store.WithAPIKey(cfg.UpstreamAPIKey),
+		store.WithCollection(cfg.UpstreamCollection),
+	)
+	if cfg.BreakerFailureThreshold > 0 {
+		storeClient = store.NewBreakerStore(storeClient, store.BreakerSettings{
+			FailureThreshold: cfg.BreakerFailureThreshold,
+			OpenTimeout:      cfg.BreakerOpenTimeout,
+			HalfOpenMaxCalls: cfg.BreakerHalfOpenMaxCalls,
+		})
+	}
+	if cfg.CacheTTL > 0 {
+		storeClient = store.NewCachedStore(storeClient, cfg.CacheTTL, cfg.CacheMaxEntries)
+	}
+
+	return storeClient
 }
@@ -0,0 +1,44 @@
+// Package memstore provides a concurrency-safe, in-memory implementation of the object store of obj-rest.
+// It can be imported outside this module, as a backend for development and as a test double for ObjectDataAccessor.
+package memstore
+
+import (
+	"github.com/harshitrajsinha/obj-rest/internal/models"
+	"github.com/harshitrajsinha/obj-rest/internal/store"
+)
+
+// ObjectDataAccessor defines the operations of an object store, it is implemented by every store of obj-rest
+type ObjectDataAccessor = store.ObjectDataAccessor
+
+// MemoryStore implements ObjectDataAccessor by holding objects in memory
+type MemoryStore = store.MemoryStore
+
+// types of the objects handled by ObjectDataAccessor
+type (
+	// ObjDataFromResponse represents an object as it is read from the store
+	ObjDataFromResponse = models.ObjDataFromResponse
+	// ObjDataPayload represents the name and data of an object to create or update
+	ObjDataPayload = models.ObjDataPayload
+	// NewObj represents an object that has been created or updated, along with its timestamps
+	NewObj = models.NewObj
+	// DeleteResult represents the outcome of deleting an object
+	DeleteResult = models.DeleteResult
+)
+
+// ErrNotFound is returned when the requested object does not exist, to be matched with errors.Is
+var ErrNotFound = store.ErrNotFound
+
+// New creates a store holding the given objects, objects created later get a generated ID
+func New(objects []NewObj) ObjectDataAccessor {
+	return store.NewMemoryStore(objects)
+}
+
+// NewFromFile creates a store holding the objects read from a JSON file
+func NewFromFile(path string) (ObjectDataAccessor, error) {
+	return store.NewMemoryStoreFromFile(path)
+}
+
+// ReservedObjects returns a copy of the 13 reserved objects of restful-api.dev, to seed a store with
+func ReservedObjects() []NewObj {
+	return store.ReservedObjects()
+}
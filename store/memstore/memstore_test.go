@@ -0,0 +1,40 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/harshitrajsinha/obj-rest/store/memstore"
+)
+
+// TestMemStore tests that the store can be used through the public package only
+func TestMemStore(t *testing.T) {
+
+	ctx := context.Background()
+	var objStore memstore.ObjectDataAccessor = memstore.New(memstore.ReservedObjects())
+
+	created, err := objStore.CreateNewObject(ctx, memstore.ObjDataPayload{
+		Name: "Apple AirPods Pro",
+		Data: map[string]interface{}{"color": "white"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	objectsList, err := objStore.GetAllObjects(ctx)
+	if err != nil || len(objectsList) != 14 || objectsList[13].ID != created.ID {
+		t.Errorf("expected reserved objects followed by created object, got %d objects, %v", len(objectsList), err)
+	}
+
+	if _, err := objStore.DeleteObject(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+	if _, err := objStore.GetObjectByID(ctx, created.ID); !errors.Is(err, memstore.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, ok := objStore.(*memstore.MemoryStore); !ok {
+		t.Errorf("expected *memstore.MemoryStore, got %T", objStore)
+	}
+}